@@ -0,0 +1,139 @@
+// Package sqlite stores client audit records in a SQLite database. It requires cgo,
+// which is why it lives outside of package client.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/client"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS bundles (
+	id               INTEGER PRIMARY KEY AUTOINCREMENT,
+	time             TEXT NOT NULL,
+	method           TEXT NOT NULL,
+	endpoint         TEXT,
+	bundle_hash      TEXT,
+	block_number     INTEGER NOT NULL,
+	txs              TEXT NOT NULL,
+	tx_hashes        TEXT,
+	replacement_uuid TEXT,
+	arg              TEXT,
+	simulation       TEXT,
+	response         TEXT,
+	error            TEXT,
+	latency_ms       REAL NOT NULL,
+	inclusion_status TEXT,
+	inclusion_block  TEXT,
+	inclusion_time   TEXT
+);
+CREATE INDEX IF NOT EXISTS bundles_hash ON bundles (bundle_hash, block_number);
+CREATE INDEX IF NOT EXISTS bundles_block ON bundles (block_number);
+`
+
+// AuditSink writes one row per bundle and fills in its inclusion status once known.
+type AuditSink struct {
+	db *sql.DB
+}
+
+var _ client.AuditSink = (*AuditSink)(nil)
+
+// NewAuditSink opens or creates the database at path.
+func NewAuditSink(path string) (*AuditSink, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?_journal_mode=WAL&_busy_timeout=5000")
+	if err != nil {
+		return nil, err
+	}
+	// a single writer avoids SQLITE_BUSY between our own connections
+	db.SetMaxOpenConns(1)
+	if _, err = db.Exec(schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &AuditSink{db: db}, nil
+}
+
+func (s *AuditSink) RecordBundle(ctx context.Context, rec *client.AuditRecord) error {
+	txs, err := json.Marshal(rec.Txs)
+	if err != nil {
+		return err
+	}
+	txHashes, err := jsonOrNull(rec.TxHashes)
+	if err != nil {
+		return err
+	}
+	simulation, err := jsonOrNull(rec.Simulation)
+	if err != nil {
+		return err
+	}
+	response, err := jsonOrNull(rec.Response)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `INSERT INTO bundles
+		(time, method, endpoint, bundle_hash, block_number, txs, tx_hashes, replacement_uuid, arg, simulation, response, error, latency_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rec.Time.UTC().Format(time.RFC3339Nano),
+		rec.Method,
+		rec.Endpoint,
+		nullString(rec.BundleHash),
+		rec.BlockNumber,
+		string(txs),
+		txHashes,
+		nullString(rec.ReplacementUuid),
+		nullString(string(rec.Arg)),
+		simulation,
+		response,
+		nullString(rec.Error),
+		float64(rec.Latency)/float64(time.Millisecond),
+	)
+	return err
+}
+
+// RecordInclusion updates the eth_sendBundle rows of the bundle for its target block.
+// Rows are matched by bundle hash, or by tx hashes when the relay returned no hash.
+func (s *AuditSink) RecordInclusion(ctx context.Context, rec *client.InclusionRecord) error {
+	status, err := rec.Status.MarshalText()
+	if err != nil {
+		return err
+	}
+	txHashes, err := jsonOrNull(rec.TxHashes)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `UPDATE bundles
+		SET inclusion_status = ?, inclusion_block = ?, inclusion_time = ?
+		WHERE method = 'eth_sendBundle' AND block_number = ?
+		AND (bundle_hash = ? OR (? IS NULL AND tx_hashes = ?))`,
+		string(status),
+		rec.BlockHash.Hex(),
+		rec.Time.UTC().Format(time.RFC3339Nano),
+		rec.BlockNumber,
+		nullString(rec.BundleHash),
+		nullString(rec.BundleHash),
+		txHashes,
+	)
+	return err
+}
+
+func (s *AuditSink) Close() error {
+	return s.db.Close()
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// jsonOrNull encodes v, storing NULL for nil values.
+func jsonOrNull(v interface{}) (sql.NullString, error) {
+	b, err := json.Marshal(v)
+	if err != nil || string(b) == "null" {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
@@ -0,0 +1,81 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/client"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+func TestRecordInclusion(t *testing.T) {
+	hashes := []gethcommon.Hash{gethcommon.HexToHash("0x01"), gethcommon.HexToHash("0x02")}
+	tests := []struct {
+		name       string
+		bundle     *client.AuditRecord
+		inclusion  *client.InclusionRecord
+		wantStatus string
+	}{
+		{
+			name:       "by bundle hash",
+			bundle:     &client.AuditRecord{Method: "eth_sendBundle", BundleHash: "0xab", BlockNumber: 10, Txs: []string{"0x01"}, TxHashes: hashes},
+			inclusion:  &client.InclusionRecord{BundleHash: "0xab", TxHashes: hashes, BlockNumber: 10, Status: client.Included},
+			wantStatus: "included",
+		},
+		{
+			name:       "by tx hashes",
+			bundle:     &client.AuditRecord{Method: "eth_sendBundle", BlockNumber: 11, Txs: []string{"0x01"}, TxHashes: hashes},
+			inclusion:  &client.InclusionRecord{TxHashes: hashes, BlockNumber: 11, Status: client.NotIncluded},
+			wantStatus: "not_included",
+		},
+		{
+			name:      "other block",
+			bundle:    &client.AuditRecord{Method: "eth_sendBundle", BundleHash: "0xab", BlockNumber: 12, Txs: []string{"0x01"}},
+			inclusion: &client.InclusionRecord{BundleHash: "0xab", BlockNumber: 13, Status: client.Included},
+		},
+		{
+			name:      "other tx hashes",
+			bundle:    &client.AuditRecord{Method: "eth_sendBundle", BlockNumber: 14, Txs: []string{"0x01"}, TxHashes: hashes[:1]},
+			inclusion: &client.InclusionRecord{TxHashes: hashes, BlockNumber: 14, Status: client.Included},
+		},
+		{
+			name:      "simulation only",
+			bundle:    &client.AuditRecord{Method: "eth_callBundle", BundleHash: "0xcd", BlockNumber: 15, Txs: []string{"0x01"}},
+			inclusion: &client.InclusionRecord{BundleHash: "0xcd", BlockNumber: 15, Status: client.Included},
+		},
+	}
+
+	sink, err := NewAuditSink(filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.bundle.Time = time.Now()
+			if err := sink.RecordBundle(ctx, tt.bundle); err != nil {
+				t.Fatal(err)
+			}
+			tt.inclusion.Time = time.Now()
+			tt.inclusion.BlockHash = gethcommon.HexToHash("0xbb")
+			if err := sink.RecordInclusion(ctx, tt.inclusion); err != nil {
+				t.Fatal(err)
+			}
+			var status, block sql.NullString
+			err := sink.db.QueryRow(`SELECT inclusion_status, inclusion_block FROM bundles WHERE block_number = ?`, tt.bundle.BlockNumber).Scan(&status, &block)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status.String != tt.wantStatus {
+				t.Fatalf("inclusion_status = %q, want %q", status.String, tt.wantStatus)
+			}
+			if tt.wantStatus != "" && block.String != tt.inclusion.BlockHash.Hex() {
+				t.Fatalf("inclusion_block = %q", block.String)
+			}
+		})
+	}
+}
@@ -0,0 +1,392 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// AuditRecord is the durable record of one bundle sent to the relay.
+type AuditRecord struct {
+	Time            time.Time                  `json:"time"`
+	Method          string                     `json:"method"` // eth_sendBundle or eth_callBundle
+	Endpoint        string                     `json:"endpoint,omitempty"`
+	BundleHash      string                     `json:"bundleHash,omitempty"`
+	BlockNumber     uint64                     `json:"blockNumber"`
+	Txs             []string                   `json:"txs"`
+	TxHashes        []gethcommon.Hash          `json:"txHashes,omitempty"`
+	ReplacementUuid string                     `json:"replacementUuid,omitempty"`
+	Arg             json.RawMessage            `json:"arg,omitempty"`        // the encoded argument when its bundles cannot be read
+	Simulation      *common.CallBundleResponse `json:"simulation,omitempty"` // from eth_callBundle or the BundleGate
+	Response        *common.SendBundleResponse `json:"response,omitempty"`
+	Error           string                     `json:"error,omitempty"`
+	Latency         time.Duration              `json:"latency"`
+}
+
+// InclusionRecord is the final status of an audited bundle, reported by a Tracker.
+type InclusionRecord struct {
+	Time        time.Time         `json:"time"`
+	BundleHash  string            `json:"bundleHash,omitempty"`
+	TxHashes    []gethcommon.Hash `json:"txHashes,omitempty"`
+	BlockNumber uint64            `json:"blockNumber"`
+	BlockHash   gethcommon.Hash   `json:"blockHash"`
+	Status      InclusionStatus   `json:"status"`
+}
+
+// AuditSink stores audit records. Failures are logged and never fail a submission.
+type AuditSink interface {
+	RecordBundle(ctx context.Context, rec *AuditRecord) error
+	RecordInclusion(ctx context.Context, rec *InclusionRecord) error
+}
+
+// auditQueueSize bounds the records waiting for the sink before writes fall back to
+// the calling goroutine.
+const auditQueueSize = 1024
+
+// auditWriter hands records to the sink on a background goroutine, keeping the sink
+// out of the submission path.
+type auditWriter struct {
+	logger *zap.Logger
+	sink   AuditSink
+	mu     sync.RWMutex // protects closed, held to send on queue
+	closed bool
+	queue  chan func(AuditSink)
+	done   chan struct{}
+}
+
+func newAuditWriter(logger *zap.Logger, sink AuditSink) *auditWriter {
+	w := &auditWriter{
+		logger: logger,
+		sink:   sink,
+		queue:  make(chan func(AuditSink), auditQueueSize),
+		done:   make(chan struct{}),
+	}
+	go w.loop()
+	return w
+}
+
+func (w *auditWriter) loop() {
+	defer close(w.done)
+	for write := range w.queue {
+		write(w.sink)
+	}
+}
+
+// enqueue schedules write, running it right away when the queue is full or the writer
+// closed rather than dropping the record.
+func (w *auditWriter) enqueue(write func(AuditSink)) {
+	w.mu.RLock()
+	if !w.closed {
+		select {
+		case w.queue <- write:
+			w.mu.RUnlock()
+			return
+		default:
+			w.logger.Warn("audit queue full, writing synchronously")
+		}
+	}
+	w.mu.RUnlock()
+	write(w.sink)
+}
+
+// close flushes the queued records.
+func (w *auditWriter) close() {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+	<-w.done
+}
+
+// SetAuditSink records every CallBundle and SendBundle. Trackers created with this
+// client record the inclusion status. Records are written in the background, Close
+// flushes them. A nil sink disables auditing after flushing the previous sink.
+func (fbc *FlashbotsClient) SetAuditSink(sink AuditSink) {
+	var w *auditWriter
+	if sink != nil {
+		w = newAuditWriter(fbc.logger, sink)
+	}
+	fbc.mu.Lock()
+	prev := fbc.audit
+	fbc.audit = w
+	fbc.mu.Unlock()
+	if prev != nil {
+		prev.close()
+	}
+}
+
+// Close flushes the audit records still queued and closes the transport when it holds
+// a connection, e.g. a WebSocketClient. The audit sink itself is left open.
+func (fbc *FlashbotsClient) Close() error {
+	fbc.SetAuditSink(nil)
+	if closer, ok := fbc.transport.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (fbc *FlashbotsClient) auditWriter() *auditWriter {
+	fbc.mu.RLock()
+	defer fbc.mu.RUnlock()
+	return fbc.audit
+}
+
+// auditBundles records one entry per bundle of arg.
+func (fbc *FlashbotsClient) auditBundles(method string, arg interface{}, start time.Time, sim *common.CallBundleResponse, res *common.SendBundleResponse, err error) {
+	w := fbc.auditWriter()
+	if w == nil {
+		return
+	}
+	latency := time.Since(start)
+	if sim == nil {
+		var rejected *BundleRejectedError
+		if errors.As(err, &rejected) {
+			sim = rejected.Simulation
+		}
+	}
+	endpoint := fbc.endpoint()
+	for _, b := range auditedBundles(arg) {
+		rec := &AuditRecord{
+			Time:            start.UTC(),
+			Method:          method,
+			Endpoint:        endpoint,
+			BlockNumber:     b.blockNumber,
+			Txs:             b.txs,
+			ReplacementUuid: b.replacementUuid,
+			Arg:             b.arg,
+			Simulation:      sim,
+			Response:        res,
+			Latency:         latency,
+		}
+		switch {
+		case res != nil:
+			rec.BundleHash = res.BundleHash
+		case sim != nil && sim.BundleHash != (gethcommon.Hash{}):
+			rec.BundleHash = sim.BundleHash.Hex()
+		}
+		if err != nil {
+			rec.Error = err.Error()
+		}
+		w.enqueue(func(sink AuditSink) {
+			rec.TxHashes = txHashes(rec.Txs)
+			if err := sink.RecordBundle(context.Background(), rec); err != nil {
+				fbc.logger.Error("failed to record audit entry", zap.String("method", method), zap.Error(err))
+			}
+		})
+	}
+}
+
+func (fbc *FlashbotsClient) auditInclusion(ev *InclusionEvent) {
+	w := fbc.auditWriter()
+	if w == nil {
+		return
+	}
+	rec := &InclusionRecord{
+		Time:        time.Now().UTC(),
+		BundleHash:  ev.Bundle.BundleHash,
+		TxHashes:    ev.Bundle.TxHashes,
+		BlockNumber: ev.Bundle.BlockNumber,
+		BlockHash:   ev.BlockHash,
+		Status:      ev.Status,
+	}
+	w.enqueue(func(sink AuditSink) {
+		if err := sink.RecordInclusion(context.Background(), rec); err != nil {
+			fbc.logger.Error("failed to record bundle inclusion", zap.String("bundleHash", rec.BundleHash), zap.Error(err))
+		}
+	})
+}
+
+// endpoint returns the URL of the transport with any password redacted.
+func (fbc *FlashbotsClient) endpoint() string {
+	var rawURL string
+	switch t := fbc.transport.(type) {
+	case *HttpClient:
+		rawURL = t.url
+	case *WebSocketClient:
+		rawURL = t.url
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Redacted()
+}
+
+type auditedBundle struct {
+	txs             []string
+	blockNumber     uint64
+	replacementUuid string
+	arg             json.RawMessage
+}
+
+// encodedBundle decodes the fields shared by the bundle arguments of eth_callBundle and
+// eth_sendBundle.
+type encodedBundle struct {
+	Txs             []string `json:"txs"`
+	BlockNumber     string   `json:"blockNumber"`
+	ReplacementUuid string   `json:"replacementUuid"`
+}
+
+func (b encodedBundle) audited() auditedBundle {
+	n, _ := hexutil.DecodeUint64(b.BlockNumber)
+	return auditedBundle{txs: b.Txs, blockNumber: n, replacementUuid: b.ReplacementUuid}
+}
+
+// auditedBundles returns the bundles of arg. Other types than the argument types of this
+// package, e.g. maps or raw JSON, are read through their JSON encoding, and recorded as
+// a single entry holding that encoding when they do not decode to bundles.
+func auditedBundles(arg interface{}) []auditedBundle {
+	var bundles []auditedBundle
+	addSend := func(a common.SendBundleArgs) {
+		bundles = append(bundles, encodedBundle{a.Txs, a.BlockNumber, a.ReplacementUuid}.audited())
+	}
+	addCall := func(a common.CallBundleArgs) {
+		bundles = append(bundles, encodedBundle{Txs: a.Txs, BlockNumber: a.BlockNumber}.audited())
+	}
+	switch a := arg.(type) {
+	case common.SendBundleArgs:
+		addSend(a)
+		return bundles
+	case *common.SendBundleArgs:
+		if a != nil {
+			addSend(*a)
+			return bundles
+		}
+	case []common.SendBundleArgs:
+		for _, v := range a {
+			addSend(v)
+		}
+		return bundles
+	case []*common.SendBundleArgs:
+		for _, v := range a {
+			if v != nil {
+				addSend(*v)
+			}
+		}
+		return bundles
+	case common.CallBundleArgs:
+		addCall(a)
+		return bundles
+	case *common.CallBundleArgs:
+		if a != nil {
+			addCall(*a)
+			return bundles
+		}
+	case []common.CallBundleArgs:
+		for _, v := range a {
+			addCall(v)
+		}
+		return bundles
+	case []*common.CallBundleArgs:
+		for _, v := range a {
+			if v != nil {
+				addCall(*v)
+			}
+		}
+		return bundles
+	}
+
+	var raw []byte
+	switch a := arg.(type) {
+	case json.RawMessage:
+		raw = a
+	case []byte:
+		raw = a
+	default:
+		var err error
+		if raw, err = json.Marshal(arg); err != nil {
+			raw, _ = json.Marshal(err.Error())
+		}
+	}
+	var list []encodedBundle
+	if err := json.Unmarshal(raw, &list); err == nil && len(list) > 0 {
+		for _, b := range list {
+			bundles = append(bundles, b.audited())
+		}
+		return bundles
+	}
+	var single encodedBundle
+	if err := json.Unmarshal(raw, &single); err == nil && single.Txs != nil {
+		return append(bundles, single.audited())
+	}
+	return append(bundles, auditedBundle{arg: append(json.RawMessage(nil), raw...)})
+}
+
+// txHashes hashes the raw txs, skipping the ones that do not decode.
+func txHashes(rawTxs []string) []gethcommon.Hash {
+	hashes := make([]gethcommon.Hash, 0, len(rawTxs))
+	for _, rawTx := range rawTxs {
+		b, err := hexutil.Decode(rawTx)
+		if err != nil {
+			continue
+		}
+		tx := new(types.Transaction)
+		if err = tx.UnmarshalBinary(b); err != nil {
+			continue
+		}
+		hashes = append(hashes, tx.Hash())
+	}
+	return hashes
+}
+
+// JSONLAuditSink appends audit records to a file, one JSON object per line with a
+// "kind" of "bundle" or "inclusion".
+type JSONLAuditSink struct {
+	mu sync.Mutex // protects f
+	f  *os.File
+}
+
+func NewJSONLAuditSink(path string) (*JSONLAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLAuditSink{f: f}, nil
+}
+
+func (s *JSONLAuditSink) RecordBundle(_ context.Context, rec *AuditRecord) error {
+	return s.write(struct {
+		Kind string `json:"kind"`
+		*AuditRecord
+	}{"bundle", rec})
+}
+
+func (s *JSONLAuditSink) RecordInclusion(_ context.Context, rec *InclusionRecord) error {
+	return s.write(struct {
+		Kind string `json:"kind"`
+		*InclusionRecord
+	}{"inclusion", rec})
+}
+
+// write appends a line and syncs it, records must survive a crash of the bot.
+func (s *JSONLAuditSink) write(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err = s.f.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return s.f.Sync()
+}
+
+func (s *JSONLAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"go.uber.org/zap"
+)
+
+func TestAuditedBundles(t *testing.T) {
+	send := common.SendBundleArgs{Txs: []string{"0x01"}, BlockNumber: "0xa", ReplacementUuid: "uuid"}
+	call := common.CallBundleArgs{Txs: []string{"0x02"}, BlockNumber: "0xb"}
+	raw, _ := json.Marshal([]common.SendBundleArgs{send, send})
+	tests := []struct {
+		name      string
+		arg       interface{}
+		want      int
+		wantBlock uint64
+		wantArg   bool
+	}{
+		{"send", send, 1, 10, false},
+		{"send pointer", &send, 1, 10, false},
+		{"send slice", []common.SendBundleArgs{send, send}, 2, 10, false},
+		{"send pointer slice", []*common.SendBundleArgs{&send, nil, &send}, 2, 10, false},
+		{"call", call, 1, 11, false},
+		{"call pointer", &call, 1, 11, false},
+		{"call slice", []common.CallBundleArgs{call}, 1, 11, false},
+		{"call pointer slice", []*common.CallBundleArgs{&call}, 1, 11, false},
+		{"raw json", json.RawMessage(raw), 2, 10, false},
+		{"map", map[string]interface{}{"txs": []string{"0x01"}, "blockNumber": "0xa"}, 1, 10, false},
+		{"unknown", struct{ Bundle int }{1}, 1, 0, true},
+		{"nil pointer", (*common.SendBundleArgs)(nil), 1, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundles := auditedBundles(tt.arg)
+			if len(bundles) != tt.want {
+				t.Fatalf("auditedBundles() = %d bundles, want %d", len(bundles), tt.want)
+			}
+			if b := bundles[0]; b.blockNumber != tt.wantBlock || (len(b.arg) > 0) != tt.wantArg {
+				t.Fatalf("auditedBundles()[0] = %+v", b)
+			}
+		})
+	}
+}
+
+// blockingSink holds every write until release is closed.
+type blockingSink struct {
+	release chan struct{}
+	mu      sync.Mutex
+	bundles []*AuditRecord
+}
+
+func (s *blockingSink) RecordBundle(ctx context.Context, rec *AuditRecord) error {
+	<-s.release
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bundles = append(s.bundles, rec)
+	return nil
+}
+
+func (s *blockingSink) records() []*AuditRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*AuditRecord(nil), s.bundles...)
+}
+
+func (s *blockingSink) RecordInclusion(ctx context.Context, rec *InclusionRecord) error {
+	return nil
+}
+
+func TestAuditInBackground(t *testing.T) {
+	txs := rawTxs(t, signedTxs(t, 2))
+	transport := &fakeTransport{results: map[string]string{_SendBundle: `{"bundleHash":"0x01"}`}}
+	fbc := &FlashbotsClient{logger: zap.NewNop(), transport: transport}
+	sink := &blockingSink{release: make(chan struct{})}
+	fbc.SetAuditSink(sink)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := fbc.SendBundle(context.Background(), []common.SendBundleArgs{{Txs: txs, BlockNumber: "0x1"}, {Txs: txs, BlockNumber: "0x2"}})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SendBundle waited for the audit sink")
+	}
+
+	close(sink.release)
+	if err := fbc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := sink.records(); len(got) != 2 {
+		t.Fatalf("flushed %d records, want 2", len(got))
+	}
+	for i, rec := range sink.records() {
+		if rec.BundleHash != "0x01" || rec.BlockNumber != uint64(i+1) || len(rec.TxHashes) != 2 {
+			t.Fatalf("record %d = %+v", i, rec)
+		}
+	}
+
+	// replacing the sink flushes the previous one
+	fbc.SetAuditSink(sink)
+	fbc.auditBundles(_SendBundle, common.SendBundleArgs{Txs: txs}, time.Now(), nil, nil, nil)
+	fbc.SetAuditSink(nil)
+	if got := sink.records(); len(got) != 3 {
+		t.Fatalf("flushed %d records, want 3", len(got))
+	}
+}
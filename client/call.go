@@ -0,0 +1,85 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+)
+
+// ErrNullResult is returned by Call when the response carries neither a result nor an error.
+var ErrNullResult = errors.New("json-rpc: null result")
+
+// Caller sends a JSON-RPC message. HttpClient, WebSocketClient and FlashbotsClient implement it.
+type Caller interface {
+	CallContext(ctx context.Context, msg interface{}) (*common.JSONRPCMessage, error)
+}
+
+// Transport is a Caller accepting interceptors, the connection used by FlashbotsClient.
+type Transport interface {
+	Caller
+	Use(interceptors ...Interceptor)
+}
+
+// Call invokes method with params, which are encoded as is and should therefore be a
+// slice or a type encoding to a JSON array, and decodes the result into Resp. It can be
+// used for relay methods this package does not wrap:
+//
+//	res, err := client.Call[[]MyArgs, MyResponse](ctx, fbc, "flashbots_newMethod", args)
+func Call[Req, Resp any](ctx context.Context, caller Caller, method string, params Req) (*Resp, error) {
+	result, err := CallRaw(ctx, caller, method, params)
+	if err != nil {
+		return nil, err
+	}
+	if isNull(result) {
+		return nil, fmt.Errorf("%s: %w", method, ErrNullResult)
+	}
+	resp := new(Resp)
+	if err = json.Unmarshal(result, resp); err != nil {
+		return nil, fmt.Errorf("%s: failed to decode result: %w", method, err)
+	}
+	return resp, nil
+}
+
+// callNullable is Call for the stats methods, which answered a null result with nil and
+// no error before Call existed and keep doing so.
+func callNullable[Resp any](ctx context.Context, caller Caller, method string, params interface{}) (*Resp, error) {
+	resp, err := Call[interface{}, Resp](ctx, caller, method, params)
+	if errors.Is(err, ErrNullResult) {
+		return nil, nil
+	}
+	return resp, err
+}
+
+// CallRaw is like Call but returns the undecoded result, which may be null.
+func CallRaw[Req any](ctx context.Context, caller Caller, method string, params Req) (json.RawMessage, error) {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to marshal params: %w", method, err)
+	}
+	request := common.NewJSONRPCMessage(method, b)
+	res, err := caller.CallContext(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, fmt.Errorf("%s: empty response", method)
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	return res.Result, nil
+}
+
+func isNull(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+	return len(raw) == 0 || bytes.Equal(raw, []byte("null"))
+}
+
+// CallContext sends msg through the transport, making FlashbotsClient a Caller.
+func (fbc *FlashbotsClient) CallContext(ctx context.Context, msg interface{}) (*common.JSONRPCMessage, error) {
+	return fbc.transport.CallContext(ctx, msg)
+}
@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"go.uber.org/zap"
+)
+
+// callerFunc answers calls with a fixed response.
+type callerFunc func(ctx context.Context, msg interface{}) (*common.JSONRPCMessage, error)
+
+func (f callerFunc) CallContext(ctx context.Context, msg interface{}) (*common.JSONRPCMessage, error) {
+	return f(ctx, msg)
+}
+
+func (f callerFunc) Use(interceptors ...Interceptor) {}
+
+func respond(res *common.JSONRPCMessage, err error) callerFunc {
+	return func(ctx context.Context, msg interface{}) (*common.JSONRPCMessage, error) {
+		return res, err
+	}
+}
+
+func TestCall(t *testing.T) {
+	type result struct {
+		BundleHash string `json:"bundleHash"`
+	}
+	errTransport := errors.New("connection refused")
+	tests := []struct {
+		name    string
+		caller  callerFunc
+		params  interface{}
+		want    string
+		wantRaw string
+		check   func(t *testing.T, err error)
+	}{
+		{
+			name:    "result",
+			caller:  respond(&common.JSONRPCMessage{Result: []byte(`{"bundleHash":"0x01"}`)}, nil),
+			want:    "0x01",
+			wantRaw: `{"bundleHash":"0x01"}`,
+		},
+		{
+			name:   "error object",
+			caller: respond(&common.JSONRPCMessage{Error: &common.JSONError{Code: -32602, Message: "invalid params"}}, nil),
+			check: func(t *testing.T, err error) {
+				var rpcErr *common.JSONError
+				if !errors.As(err, &rpcErr) || rpcErr.Code != -32602 {
+					t.Fatalf("error = %v, want the JSON-RPC error", err)
+				}
+			},
+		},
+		{
+			name:    "null result",
+			caller:  respond(&common.JSONRPCMessage{Result: []byte(`null`)}, nil),
+			wantRaw: `null`,
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, ErrNullResult) {
+					t.Fatalf("error = %v, want ErrNullResult", err)
+				}
+			},
+		},
+		{
+			name:   "missing result",
+			caller: respond(&common.JSONRPCMessage{}, nil),
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, ErrNullResult) {
+					t.Fatalf("error = %v, want ErrNullResult", err)
+				}
+			},
+		},
+		{
+			name:    "decode failure",
+			caller:  respond(&common.JSONRPCMessage{Result: []byte(`"0x01"`)}, nil),
+			wantRaw: `"0x01"`,
+			check: func(t *testing.T, err error) {
+				if err == nil || !strings.Contains(err.Error(), "failed to decode result") {
+					t.Fatalf("error = %v, want a decode error", err)
+				}
+			},
+		},
+		{
+			name:   "nil response",
+			caller: respond(nil, nil),
+			check: func(t *testing.T, err error) {
+				if err == nil || !strings.Contains(err.Error(), "empty response") {
+					t.Fatalf("error = %v, want an empty response error", err)
+				}
+			},
+		},
+		{
+			name:   "transport error",
+			caller: respond(nil, errTransport),
+			check: func(t *testing.T, err error) {
+				if !errors.Is(err, errTransport) {
+					t.Fatalf("error = %v, want %v", err, errTransport)
+				}
+			},
+		},
+		{
+			name:   "unencodable params",
+			caller: respond(&common.JSONRPCMessage{Result: []byte(`{}`)}, nil),
+			params: []interface{}{make(chan int)},
+			check: func(t *testing.T, err error) {
+				if err == nil || !strings.Contains(err.Error(), "failed to marshal params") {
+					t.Fatalf("error = %v, want a marshal error", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := tt.params
+			if params == nil {
+				params = []string{"0x01"}
+			}
+			res, err := Call[interface{}, result](context.Background(), tt.caller, "eth_test", params)
+			if tt.check != nil {
+				tt.check(t, err)
+				if res != nil {
+					t.Fatalf("Call() = %+v with an error", res)
+				}
+			} else if err != nil || res.BundleHash != tt.want {
+				t.Fatalf("Call() = %+v, %v, want %s", res, err, tt.want)
+			}
+
+			raw, err := CallRaw(context.Background(), tt.caller, "eth_test", params)
+			if tt.wantRaw != "" {
+				if err != nil || string(raw) != tt.wantRaw {
+					t.Fatalf("CallRaw() = %s, %v, want %s", raw, err, tt.wantRaw)
+				}
+			} else if err == nil && len(raw) != 0 {
+				t.Fatalf("CallRaw() = %s, want no result", raw)
+			}
+		})
+	}
+}
+
+func TestCallRequest(t *testing.T) {
+	var got common.JSONRPCMessage
+	caller := callerFunc(func(ctx context.Context, msg interface{}) (*common.JSONRPCMessage, error) {
+		got = msg.(common.JSONRPCMessage)
+		return &common.JSONRPCMessage{Result: []byte(`true`)}, nil
+	})
+	res, err := Call[[]common.CancelBundleArgs, bool](context.Background(), caller, _CancelBundle,
+		[]common.CancelBundleArgs{{ReplacementUuid: "7c3b4f6e-0000-4000-8000-000000000000"}})
+	if err != nil || !*res {
+		t.Fatalf("Call() = %v, %v", res, err)
+	}
+	if got.Method != _CancelBundle || got.Version != common.JSONRPCVersion ||
+		string(got.Params) != `[{"replacementUuid":"7c3b4f6e-0000-4000-8000-000000000000"}]` {
+		t.Fatalf("request = %s %s %s", got.Version, got.Method, got.Params)
+	}
+}
+
+func TestStatsNullResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		result string
+		call   func(fbc *FlashbotsClient) (interface{}, error)
+	}{
+		{"bundle stats", `{"isSimulated":true}`, func(fbc *FlashbotsClient) (interface{}, error) {
+			return fbc.BundleStats(context.Background(), []common.BundleStatsArgs{{}})
+		}},
+		{"bundle stats v2", `{"isSimulated":true}`, func(fbc *FlashbotsClient) (interface{}, error) {
+			return fbc.BundleStatsV2(context.Background(), []common.BundleStatsArgs{{}})
+		}},
+		{"user stats", `{"is_high_priority":true}`, func(fbc *FlashbotsClient) (interface{}, error) {
+			return fbc.UserStats(context.Background(), []common.UserStatsArgs{{}})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fbc := &FlashbotsClient{logger: zap.NewNop(), transport: respond(&common.JSONRPCMessage{Result: []byte(`null`)}, nil)}
+			res, err := tt.call(fbc)
+			if err != nil {
+				t.Fatalf("null result: error = %v, want none", err)
+			}
+			if !reflect.ValueOf(res).IsNil() {
+				t.Fatalf("null result: got %+v, want nil", res)
+			}
+
+			fbc.transport = respond(&common.JSONRPCMessage{Result: []byte(tt.result)}, nil)
+			if res, err = tt.call(fbc); err != nil {
+				t.Fatal(err)
+			}
+			if reflect.ValueOf(res).IsNil() {
+				t.Fatal("decoded result is nil")
+			}
+
+			fbc.transport = respond(&common.JSONRPCMessage{Error: &common.JSONError{Code: -32000, Message: "unknown bundle"}}, nil)
+			if _, err = tt.call(fbc); err == nil {
+				t.Fatal("JSON-RPC error not returned")
+			}
+		})
+	}
+}
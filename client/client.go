@@ -2,7 +2,9 @@ package client
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"sync"
+	"time"
 
 	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
 	"go.uber.org/zap"
@@ -10,22 +12,28 @@ import (
 
 const (
 	// V1 methods
-	_CallBundle = "eth_callBundle"
-	_SendBundle = "eth_sendBundle"
-	//_CancelBundle    = "eth_cancelBundle"
-	_UserStats       = "flashbots_getUserStats"
-	_BundleStats     = "flashbots_getBundleStats"
-	_SendPrivateTx   = "eth_sendPrivateTransaction"
-	_CancelPrivateTx = "eth_cancelPrivateTransaction"
+	_CallBundle       = "eth_callBundle"
+	_SendBundle       = "eth_sendBundle"
+	_CancelBundle     = "eth_cancelBundle"
+	_UserStats        = "flashbots_getUserStats"
+	_BundleStats      = "flashbots_getBundleStats"
+	_SendPrivateTx    = "eth_sendPrivateTransaction"
+	_CancelPrivateTx  = "eth_cancelPrivateTransaction"
+	_SendPrivateRawTx = "eth_sendPrivateRawTransaction"
 
 	// V2 methods
 	//_UserStatsV2   = "flashbots_getUserStatsV2"
-	//_BundleStatsV2 = "flashbots_getBundleStatsV2"
+	_BundleStatsV2 = "flashbots_getBundleStatsV2"
 )
 
 type FlashbotsClient struct {
-	logger     *zap.Logger
-	httpClient *HttpClient
+	logger    *zap.Logger
+	transport Transport
+
+	mu      sync.RWMutex // guards gate, network and audit
+	gate    *BundleGate
+	network *common.Network
+	audit   *auditWriter
 }
 
 func NewFlashbotsClient(url string) *FlashbotsClient {
@@ -36,8 +44,8 @@ func NewFlashbotsClient(url string) *FlashbotsClient {
 	}
 
 	return &FlashbotsClient{
-		logger:     logger,
-		httpClient: httpClient,
+		logger:    logger,
+		transport: httpClient,
 	}
 }
 
@@ -49,127 +57,151 @@ func NewFlashbotsClientWithSigner(url, signerKey string) *FlashbotsClient {
 	}
 
 	return &FlashbotsClient{
-		logger:     logger,
-		httpClient: httpClient,
+		logger:    logger,
+		transport: httpClient,
 	}
 }
 
-func (fbc *FlashbotsClient) CallBundle(ctx context.Context, arg interface{}) (*common.CallBundleResponse, error) {
-	b, err := json.Marshal(arg)
-	if err != nil {
-		fbc.logger.Error("failed to marshal param", zap.Error(err))
-		return nil, err
-	}
-	msg := json.RawMessage(b)
-	request := common.NewJSONRPCMessage(_CallBundle, msg)
-	res, err := fbc.httpClient.CallContext(ctx, request)
-	if err != nil {
-		return nil, err
+// NewFlashbotsClientWithHttpClient wraps an HttpClient configured by the caller.
+func NewFlashbotsClientWithHttpClient(httpClient *HttpClient) *FlashbotsClient {
+	return NewFlashbotsClientWithTransport(httpClient)
+}
+
+// NewFlashbotsClientWithTransport wraps any transport, e.g. a WebSocketClient.
+func NewFlashbotsClientWithTransport(transport Transport) *FlashbotsClient {
+	return &FlashbotsClient{
+		logger:    common.NewLogger(),
+		transport: transport,
 	}
-	var callBundleResponse *common.CallBundleResponse
-	if err = json.Unmarshal(res.Result, &callBundleResponse); err != nil {
+}
+
+func (fbc *FlashbotsClient) CallBundle(ctx context.Context, arg interface{}) (*common.CallBundleResponse, error) {
+	if err := fbc.checkTxs(arg); err != nil {
 		return nil, err
 	}
-	return callBundleResponse, nil
+	start := time.Now()
+	res, err := Call[interface{}, common.CallBundleResponse](ctx, fbc.transport, _CallBundle, arg)
+	fbc.auditBundles(_CallBundle, arg, start, res, nil, err)
+	return res, err
 }
 
+// BundleStats returns nil and no error when the relay answers with a null result.
 func (fbc *FlashbotsClient) BundleStats(ctx context.Context, arg interface{}) (*common.BundleStatsResponse, error) {
-	b, err := json.Marshal(arg)
-	if err != nil {
-		fbc.logger.Error("failed to marshal param", zap.Error(err))
-		return nil, err
-	}
-	msg := json.RawMessage(b)
-	request := common.NewJSONRPCMessage(_BundleStats, msg)
-	res, err := fbc.httpClient.CallContext(ctx, request)
-	if err != nil {
-		return nil, err
-	}
-	var bundleStatsResponse *common.BundleStatsResponse
-	if err = json.Unmarshal(res.Result, &bundleStatsResponse); err != nil {
-		return nil, err
-	}
-	return bundleStatsResponse, nil
+	return callNullable[common.BundleStatsResponse](ctx, fbc.transport, _BundleStats, arg)
 }
 
+// BundleStatsV2 returns nil and no error when the relay answers with a null result.
+func (fbc *FlashbotsClient) BundleStatsV2(ctx context.Context, arg interface{}) (*common.BundleStatsResponseV2, error) {
+	return callNullable[common.BundleStatsResponseV2](ctx, fbc.transport, _BundleStatsV2, arg)
+}
+
+// UserStats returns nil and no error when the relay answers with a null result.
 func (fbc *FlashbotsClient) UserStats(ctx context.Context, arg interface{}) (*common.UserStatsResponse, error) {
-	b, err := json.Marshal(arg)
-	if err != nil {
-		fbc.logger.Error("failed to marshal param", zap.Error(err))
-		return nil, err
-	}
-	msg := json.RawMessage(b)
-	request := common.NewJSONRPCMessage(_UserStats, msg)
-	res, err := fbc.httpClient.CallContext(ctx, request)
-	if err != nil {
-		return nil, err
-	}
-	var userStatsResponse *common.UserStatsResponse
-	if err = json.Unmarshal(res.Result, &userStatsResponse); err != nil {
-		return nil, err
-	}
-	return userStatsResponse, nil
+	return callNullable[common.UserStatsResponse](ctx, fbc.transport, _UserStats, arg)
 }
 
+// SendBundle submits the bundle. When a BundleGate is set, every bundle of arg is
+// simulated and checked first.
 func (fbc *FlashbotsClient) SendBundle(ctx context.Context, arg interface{}) (*common.SendBundleResponse, error) {
-	b, err := json.Marshal(arg)
-	if err != nil {
-		fbc.logger.Error("failed to marshal param", zap.Error(err))
+	if err := fbc.checkTxs(arg); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	var sim *common.CallBundleResponse
+	if gate := fbc.bundleGate(); gate != nil {
+		bundles, err := gatedBundles(arg)
+		if err != nil {
+			return nil, err
+		}
+		for _, bundle := range bundles {
+			if sim, err = fbc.CheckBundle(ctx, bundle, gate); err != nil {
+				fbc.auditBundles(_SendBundle, arg, start, sim, nil, err)
+				return nil, err
+			}
+		}
+		if len(bundles) != 1 {
+			// a single simulation does not describe the whole submission
+			sim = nil
+		}
+	}
+	res, err := Call[interface{}, common.SendBundleResponse](ctx, fbc.transport, _SendBundle, arg)
+	fbc.auditBundles(_SendBundle, arg, start, sim, res, err)
+	return res, err
+}
+
+func (fbc *FlashbotsClient) CancelBundle(ctx context.Context, arg interface{}) error {
+	_, err := CallRaw(ctx, fbc.transport, _CancelBundle, arg)
+	return err
+}
+
+func (fbc *FlashbotsClient) SendPrivateTransaction(ctx context.Context, arg interface{}) (*common.SendPrivateTransactionResponse, error) {
+	if err := fbc.checkTxs(arg); err != nil {
 		return nil, err
 	}
-	msg := json.RawMessage(b)
-	request := common.NewJSONRPCMessage(_SendBundle, msg)
-	res, err := fbc.httpClient.CallContext(ctx, request)
-	if err != nil {
+	if err := validatePrivateTxArgs(arg); err != nil {
 		return nil, err
 	}
-	var sendBundleResponse *common.SendBundleResponse
-	if err = json.Unmarshal(res.Result, &sendBundleResponse); err != nil {
+	txHash, err := Call[interface{}, string](ctx, fbc.transport, _SendPrivateTx, arg)
+	if err != nil {
 		return nil, err
 	}
-	return sendBundleResponse, nil
+	return &common.SendPrivateTransactionResponse{TxHash: *txHash}, nil
 }
 
-func (fbc *FlashbotsClient) SendPrivateTransaction(ctx context.Context, arg interface{}) (*common.SendPrivateTransactionResponse, error) {
-	b, err := json.Marshal(arg)
-	if err != nil {
-		fbc.logger.Error("failed to marshal param", zap.Error(err))
+// SendPrivateRawTransaction expects a common.SendPrivateRawTxArgs or a pointer to one,
+// which encodes the positional params itself.
+func (fbc *FlashbotsClient) SendPrivateRawTransaction(ctx context.Context, arg interface{}) (*common.SendPrivateTransactionResponse, error) {
+	if err := fbc.checkTxs(arg); err != nil {
 		return nil, err
 	}
-	msg := json.RawMessage(b)
-	request := common.NewJSONRPCMessage(_SendPrivateTx, msg)
-	res, err := fbc.httpClient.CallContext(ctx, request)
-	if err != nil {
+	if err := validatePrivateTxArgs(arg); err != nil {
 		return nil, err
 	}
-	if res.Error != nil {
-		return nil, res.Error
-	}
-	var txHash string
-	if err = json.Unmarshal(res.Result, &txHash); err != nil {
+	txHash, err := Call[interface{}, string](ctx, fbc.transport, _SendPrivateRawTx, arg)
+	if err != nil {
 		return nil, err
 	}
-	return &common.SendPrivateTransactionResponse{TxHash: txHash}, nil
+	return &common.SendPrivateTransactionResponse{TxHash: *txHash}, nil
 }
 
 func (fbc *FlashbotsClient) CancelPrivateTransaction(ctx context.Context, arg interface{}) (*common.CancelPrivateTransactionResponse, error) {
-	b, err := json.Marshal(arg)
-	if err != nil {
-		fbc.logger.Error("failed to marshal param", zap.Error(err))
-		return nil, err
-	}
-	msg := json.RawMessage(b)
-	request := common.NewJSONRPCMessage(_CancelPrivateTx, msg)
-	res, err := fbc.httpClient.CallContext(ctx, request)
+	isCancelled, err := Call[interface{}, bool](ctx, fbc.transport, _CancelPrivateTx, arg)
 	if err != nil {
 		return nil, err
 	}
-	if res.Error != nil {
-		return nil, res.Error
-	}
-	var isCancelled bool
-	if err = json.Unmarshal(res.Result, &isCancelled); err != nil {
-		return nil, err
-	}
-	return &common.CancelPrivateTransactionResponse{IsCancelled: isCancelled}, nil
+	return &common.CancelPrivateTransactionResponse{IsCancelled: *isCancelled}, nil
+}
+
+var errNilArgs = errors.New("nil private tx args")
+
+func validatePrivateTxArgs(arg interface{}) error {
+	switch a := arg.(type) {
+	case common.SendPrivateTxArgs:
+		return a.Validate()
+	case *common.SendPrivateTxArgs:
+		if a == nil {
+			return errNilArgs
+		}
+		return a.Validate()
+	case []common.SendPrivateTxArgs:
+		for _, v := range a {
+			if err := v.Validate(); err != nil {
+				return err
+			}
+		}
+	case []*common.SendPrivateTxArgs:
+		for _, v := range a {
+			if err := validatePrivateTxArgs(v); err != nil {
+				return err
+			}
+		}
+	case common.SendPrivateRawTxArgs:
+		return a.Validate()
+	case *common.SendPrivateRawTxArgs:
+		if a == nil {
+			return errNilArgs
+		}
+		return a.Validate()
+	}
+	return nil
 }
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+)
+
+func TestValidatePrivateTxArgs(t *testing.T) {
+	valid := common.SendPrivateTxArgs{Tx: "0x01"}
+	validRaw := common.SendPrivateRawTxArgs{Tx: "0x01"}
+	tests := []struct {
+		name    string
+		arg     interface{}
+		wantErr bool
+	}{
+		{"value", valid, false},
+		{"pointer", &valid, false},
+		{"nil pointer", (*common.SendPrivateTxArgs)(nil), true},
+		{"empty tx", common.SendPrivateTxArgs{}, true},
+		{"slice", []common.SendPrivateTxArgs{valid, {}}, true},
+		{"pointer slice", []*common.SendPrivateTxArgs{&valid}, false},
+		{"pointer slice with nil", []*common.SendPrivateTxArgs{&valid, nil}, true},
+		{"raw value", validRaw, false},
+		{"raw pointer", &validRaw, false},
+		{"raw nil pointer", (*common.SendPrivateRawTxArgs)(nil), true},
+		{"raw empty tx", &common.SendPrivateRawTxArgs{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validatePrivateTxArgs(tt.arg); (err != nil) != tt.wantErr {
+				t.Fatalf("validatePrivateTxArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TransportOptions tunes the connections of an HttpClient for latency-critical submission.
+type TransportOptions struct {
+	// ForceHTTP2 negotiates HTTP/2 and fails requests answered over HTTP/1.x. Requires https.
+	ForceHTTP2 bool
+	// KeepAlive is the TCP keep-alive period, the default of net.Dialer when zero.
+	KeepAlive time.Duration
+	// IdleConnTimeout closes connections idle for longer, 90s when zero.
+	IdleConnTimeout time.Duration
+	// MaxIdleConnsPerHost bounds the idle connections kept per host, 16 when zero.
+	MaxIdleConnsPerHost int
+	// PreResolve resolves the endpoint host on Warmup and dials the cached addresses,
+	// taking DNS out of the request path.
+	PreResolve bool
+}
+
+// RequestTiming breaks down the latency of one request, up to the response headers.
+type RequestTiming struct {
+	Method    string        // JSON-RPC method, empty for Warmup
+	Proto     string        // e.g. "HTTP/2.0"
+	Reused    bool          // an idle connection was reused
+	DNS       time.Duration // zero when resolved ahead or reused
+	Connect   time.Duration
+	TLS       time.Duration
+	FirstByte time.Duration // from sending the request to the first response byte
+	Total     time.Duration
+}
+
+// SetTransportOptions replaces the transport of the underlying http.Client, so it must be
+// called before SetTransport or RecordTo. Not safe to call concurrently with requests.
+func (hc *HttpClient) SetTransportOptions(opts TransportOptions) error {
+	u, err := url.Parse(hc.url)
+	if err != nil {
+		return err
+	}
+	if opts.ForceHTTP2 && u.Scheme != "https" {
+		return fmt.Errorf("http/2 requires https, got %q", hc.url)
+	}
+	if opts.IdleConnTimeout == 0 {
+		opts.IdleConnTimeout = 90 * time.Second
+	}
+	if opts.MaxIdleConnsPerHost == 0 {
+		opts.MaxIdleConnsPerHost = 16
+	}
+	dialer := &net.Dialer{Timeout: 5 * time.Second, KeepAlive: opts.KeepAlive}
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         dialer.DialContext,
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: opts.MaxIdleConnsPerHost,
+		IdleConnTimeout:     opts.IdleConnTimeout,
+		TLSHandshakeTimeout: 5 * time.Second,
+	}
+	if opts.ForceHTTP2 {
+		transport.TLSClientConfig = &tls.Config{NextProtos: []string{"h2"}}
+	}
+	hc.resolver = nil
+	if opts.PreResolve {
+		hc.resolver = &hostCache{addrs: make(map[string][]string)}
+		transport.DialContext = hc.resolver.dialContext(dialer)
+	}
+	hc.transportOpts = opts
+	hc.client.Transport = transport
+	return nil
+}
+
+// SetTimingObserver calls fn with the timing of every request, nil disables it.
+// Not safe to call concurrently with requests.
+func (hc *HttpClient) SetTimingObserver(fn func(RequestTiming)) {
+	hc.observeTiming = fn
+}
+
+// Warmup resolves the endpoint host when PreResolve is set and opens a connection with a
+// HEAD request, whose status is ignored. Call it ahead of the slot boundary, or use
+// KeepWarm, so that submission reuses a hot connection.
+func (hc *HttpClient) Warmup(ctx context.Context) (*RequestTiming, error) {
+	if hc.resolver != nil {
+		u, err := url.Parse(hc.url)
+		if err != nil {
+			return nil, err
+		}
+		if err = hc.resolver.resolve(ctx, u.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, hc.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	request, timing := traceRequest(request, "")
+	resp, err := hc.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	t := timing(resp)
+	if err = hc.checkProto(resp); err != nil {
+		return &t, err
+	}
+	return &t, nil
+}
+
+// KeepWarm calls Warmup every interval until ctx is done, so idle connections are not closed.
+func (hc *HttpClient) KeepWarm(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := hc.Warmup(ctx); err != nil && ctx.Err() == nil {
+			hc.logger.Warn("failed to warm up connection", zap.String("url", hc.url), zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Warmup opens the connection of the transport ahead of the first request.
+func (fbc *FlashbotsClient) Warmup(ctx context.Context) error {
+	switch t := fbc.transport.(type) {
+	case *HttpClient:
+		_, err := t.Warmup(ctx)
+		return err
+	case *WebSocketClient:
+		_, err := t.connect(ctx)
+		return err
+	}
+	return nil
+}
+
+// WarmupAll warms up the clients concurrently, e.g. those of config.NewRelayClients, and
+// returns the errors by client name.
+func WarmupAll(ctx context.Context, clients map[string]*FlashbotsClient) map[string]error {
+	var (
+		mu   sync.Mutex // protects errs
+		errs = make(map[string]error)
+		wg   sync.WaitGroup
+	)
+	for name, fbc := range clients {
+		wg.Add(1)
+		go func(name string, fbc *FlashbotsClient) {
+			defer wg.Done()
+			if err := fbc.Warmup(ctx); err != nil {
+				mu.Lock()
+				errs[name] = err
+				mu.Unlock()
+			}
+		}(name, fbc)
+	}
+	wg.Wait()
+	return errs
+}
+
+func (hc *HttpClient) checkProto(resp *http.Response) error {
+	if hc.transportOpts.ForceHTTP2 && resp.ProtoMajor != 2 {
+		return fmt.Errorf("%s answered over %s, http/2 required", hc.url, resp.Proto)
+	}
+	return nil
+}
+
+// traceRequest attaches an httptrace to request. The returned function completes the
+// timing once the response headers are read.
+func traceRequest(request *http.Request, method string) (*http.Request, func(*http.Response) RequestTiming) {
+	var (
+		mu     sync.Mutex // dials may run on another goroutine
+		timing = RequestTiming{Method: method}
+		start  = time.Now()
+
+		dnsStart, connStart, tlsStart, wrote time.Time
+	)
+	record := func(fn func()) {
+		mu.Lock()
+		fn()
+		mu.Unlock()
+	}
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			record(func() { dnsStart = time.Now() })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			record(func() { timing.DNS = time.Since(dnsStart) })
+		},
+		ConnectStart: func(string, string) {
+			record(func() { connStart = time.Now() })
+		},
+		ConnectDone: func(string, string, error) {
+			record(func() { timing.Connect = time.Since(connStart) })
+		},
+		TLSHandshakeStart: func() {
+			record(func() { tlsStart = time.Now() })
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			record(func() { timing.TLS = time.Since(tlsStart) })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			record(func() { timing.Reused = info.Reused })
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			record(func() { wrote = time.Now() })
+		},
+		GotFirstResponseByte: func() {
+			record(func() { timing.FirstByte = time.Since(wrote) })
+		},
+	}
+	request = request.WithContext(httptrace.WithClientTrace(request.Context(), trace))
+	return request, func(resp *http.Response) RequestTiming {
+		mu.Lock()
+		defer mu.Unlock()
+		timing.Total = time.Since(start)
+		if resp != nil {
+			timing.Proto = resp.Proto
+		}
+		return timing
+	}
+}
+
+// hostCache holds addresses resolved ahead of dialing.
+type hostCache struct {
+	mu    sync.RWMutex // protects addrs
+	addrs map[string][]string
+}
+
+func (c *hostCache) resolve(ctx context.Context, host string) error {
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.addrs[host] = addrs
+	c.mu.Unlock()
+	return nil
+}
+
+// dialContext dials the cached addresses of the host in parallel, using the first
+// connection established and falling back to a regular dial when all of them fail.
+func (c *hostCache) dialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.RLock()
+		addrs := c.addrs[host]
+		c.mu.RUnlock()
+		if len(addrs) == 0 {
+			return dialer.DialContext(ctx, network, addr)
+		}
+
+		type dialResult struct {
+			conn net.Conn
+			err  error
+		}
+		dialCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		results := make(chan dialResult, len(addrs))
+		for _, ip := range addrs {
+			go func(ip string) {
+				conn, err := dialer.DialContext(dialCtx, network, net.JoinHostPort(ip, port))
+				results <- dialResult{conn, err}
+			}(ip)
+		}
+		var errs []error
+		for range addrs {
+			res := <-results
+			if res.err != nil {
+				errs = append(errs, res.err)
+				continue
+			}
+			// close the connections of the dials still running once they complete
+			go func(pending int) {
+				for i := 0; i < pending; i++ {
+					if res := <-results; res.conn != nil {
+						res.conn.Close()
+					}
+				}
+			}(len(addrs) - len(errs) - 1)
+			return res.conn, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		conn, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, fmt.Errorf("%w (cached addresses: %v)", err, errs)
+		}
+		return conn, nil
+	}
+}
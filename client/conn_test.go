@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHostCacheDial(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+
+	tests := []struct {
+		name    string
+		addrs   []string
+		wantErr bool
+	}{
+		// 192.0.2.0/24 is reserved for documentation and never answers
+		{"first address unreachable", []string{"192.0.2.1", "127.0.0.1"}, false},
+		{"single address", []string{"127.0.0.1"}, false},
+		{"all addresses fail", []string{"192.0.2.1"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &hostCache{addrs: map[string][]string{"relay.invalid": tt.addrs}}
+			dial := cache.dialContext(&net.Dialer{Timeout: 500 * time.Millisecond})
+			start := time.Now()
+			conn, err := dial(context.Background(), "tcp", net.JoinHostPort("relay.invalid", port))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("dial error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), "cached addresses") {
+					t.Fatalf("dial error = %v, want the cached address errors", err)
+				}
+				return
+			}
+			conn.Close()
+			if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+				t.Fatalf("dial took %v, cached addresses were not dialed in parallel", elapsed)
+			}
+		})
+	}
+}
+
+func TestWarmupAll(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer up.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	down.Close()
+
+	clients := make(map[string]*FlashbotsClient)
+	for name, url := range map[string]string{"a": up.URL, "b": up.URL, "down": down.URL} {
+		hc, err := DialHttpClient(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clients[name] = NewFlashbotsClientWithHttpClient(hc)
+	}
+	errs := WarmupAll(context.Background(), clients)
+	if len(errs) != 1 || errs["down"] == nil {
+		t.Fatalf("WarmupAll() = %v, want an error for down only", errs)
+	}
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/util"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"go.uber.org/zap"
+)
+
+// ErrBundleRejected is wrapped by every BundleRejectedError.
+var ErrBundleRejected = errors.New("bundle rejected before submission")
+
+// BundleGate holds the checks a bundle must pass in simulation before SendBundle submits it.
+type BundleGate struct {
+	MinCoinbasePayment *big.Int // optional floor on the simulated coinbase diff
+	MinBundleGasPrice  *big.Int // optional floor on the simulated bundle gas price
+	StateBlockNumber   string   // state to simulate on, defaults to "latest"
+	ContractABI        *abi.ABI // optional, used to decode custom revert errors
+}
+
+// BundleRejectedError explains why a bundle did not pass its BundleGate.
+type BundleRejectedError struct {
+	Reason     string
+	Simulation *common.CallBundleResponse
+	Reverts    *util.RevertSummary
+}
+
+func (err *BundleRejectedError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrBundleRejected, err.Reason)
+}
+
+func (err *BundleRejectedError) Unwrap() error {
+	return ErrBundleRejected
+}
+
+// SetBundleGate makes SendBundle simulate and check bundles before submission. A nil gate disables it.
+func (fbc *FlashbotsClient) SetBundleGate(gate *BundleGate) {
+	fbc.mu.Lock()
+	defer fbc.mu.Unlock()
+	fbc.gate = gate
+}
+
+func (fbc *FlashbotsClient) bundleGate() *BundleGate {
+	fbc.mu.RLock()
+	defer fbc.mu.RUnlock()
+	return fbc.gate
+}
+
+// CheckBundle simulates arg with eth_callBundle and returns a *BundleRejectedError if a tx that is not
+// in RevertingTxHashes reverts or the payment is below the floors of gate. The simulation is not
+// audited, the gated send records it with the bundle.
+func (fbc *FlashbotsClient) CheckBundle(ctx context.Context, arg common.SendBundleArgs, gate *BundleGate) (*common.CallBundleResponse, error) {
+	stateBlock := "latest"
+	if gate != nil && gate.StateBlockNumber != "" {
+		stateBlock = gate.StateBlockNumber
+	}
+	simArg := []common.CallBundleArgs{{
+		Txs:              arg.Txs,
+		BlockNumber:      arg.BlockNumber,
+		StateBlockNumber: stateBlock,
+		Timestamp:        arg.MinTimestamp,
+	}}
+	if err := fbc.checkTxs(simArg); err != nil {
+		return nil, err
+	}
+	sim, err := Call[[]common.CallBundleArgs, common.CallBundleResponse](ctx, fbc.transport, _CallBundle, simArg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate bundle: %w", err)
+	}
+	if sim == nil {
+		return nil, errors.New("failed to simulate bundle: empty result")
+	}
+	if gate == nil {
+		gate = &BundleGate{}
+	}
+
+	reverts := util.SummarizeReverts(sim, arg.RevertingTxHashes, gate.ContractABI)
+	if !reverts.OK() {
+		failed := reverts.Reverts[reverts.FirstFailure]
+		reason := fmt.Sprintf("tx %d (%s) reverted", failed.Index, failed.TxHash.Hex())
+		if failed.Reason != nil {
+			reason += ": " + failed.Reason.String()
+		} else if failed.Error != "" {
+			reason += ": " + failed.Error
+		}
+		return sim, fbc.reject(reason, sim, reverts)
+	}
+	if gate.MinCoinbasePayment != nil && (sim.CoinbaseDiff == nil || sim.CoinbaseDiff.Cmp(gate.MinCoinbasePayment) < 0) {
+		reason := fmt.Sprintf("coinbase payment %v below floor %v", sim.CoinbaseDiff, gate.MinCoinbasePayment)
+		return sim, fbc.reject(reason, sim, reverts)
+	}
+	if gate.MinBundleGasPrice != nil && (sim.BundleGasPrice == nil || sim.BundleGasPrice.Cmp(gate.MinBundleGasPrice) < 0) {
+		reason := fmt.Sprintf("bundle gas price %v below floor %v", sim.BundleGasPrice, gate.MinBundleGasPrice)
+		return sim, fbc.reject(reason, sim, reverts)
+	}
+	return sim, nil
+}
+
+func (fbc *FlashbotsClient) reject(reason string, sim *common.CallBundleResponse, reverts *util.RevertSummary) error {
+	fbc.logger.Warn("bundle rejected", zap.String("reason", reason), zap.String("bundleHash", sim.BundleHash.Hex()))
+	return &BundleRejectedError{
+		Reason:     reason,
+		Simulation: sim,
+		Reverts:    reverts,
+	}
+}
+
+// gatedBundles returns the bundles of a SendBundle argument. Other argument types cannot be
+// checked and are rejected while a gate is set.
+func gatedBundles(arg interface{}) ([]common.SendBundleArgs, error) {
+	switch a := arg.(type) {
+	case common.SendBundleArgs:
+		return []common.SendBundleArgs{a}, nil
+	case *common.SendBundleArgs:
+		if a != nil {
+			return []common.SendBundleArgs{*a}, nil
+		}
+	case []common.SendBundleArgs:
+		return a, nil
+	case []*common.SendBundleArgs:
+		bundles := make([]common.SendBundleArgs, 0, len(a))
+		for _, b := range a {
+			if b == nil {
+				return nil, errors.New("bundle gate: nil bundle")
+			}
+			bundles = append(bundles, *b)
+		}
+		return bundles, nil
+	}
+	return nil, fmt.Errorf("bundle gate: cannot check argument of type %T", arg)
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"go.uber.org/zap"
+)
+
+// fakeTransport answers every call of a method with the same result.
+type fakeTransport struct {
+	mu      sync.Mutex
+	results map[string]string
+	calls   []string
+}
+
+func (f *fakeTransport) CallContext(ctx context.Context, msg interface{}) (*common.JSONRPCMessage, error) {
+	req := msg.(common.JSONRPCMessage)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, req.Method)
+	result, ok := f.results[req.Method]
+	if !ok {
+		return nil, errors.New("unexpected method " + req.Method)
+	}
+	return &common.JSONRPCMessage{ID: req.ID, Result: []byte(result)}, nil
+}
+
+func (f *fakeTransport) Use(interceptors ...Interceptor) {}
+
+func (f *fakeTransport) count(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, m := range f.calls {
+		if m == method {
+			n++
+		}
+	}
+	return n
+}
+
+func TestGatedBundles(t *testing.T) {
+	bundle := common.SendBundleArgs{Txs: []string{"0x01"}}
+	tests := []struct {
+		name    string
+		arg     interface{}
+		want    int
+		wantErr bool
+	}{
+		{"value", bundle, 1, false},
+		{"pointer", &bundle, 1, false},
+		{"nil pointer", (*common.SendBundleArgs)(nil), 0, true},
+		{"slice", []common.SendBundleArgs{bundle, bundle}, 2, false},
+		{"pointer slice", []*common.SendBundleArgs{&bundle, &bundle}, 2, false},
+		{"pointer slice with nil", []*common.SendBundleArgs{&bundle, nil}, 0, true},
+		{"map", map[string]interface{}{"txs": []string{"0x01"}}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundles, err := gatedBundles(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("gatedBundles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(bundles) != tt.want {
+				t.Fatalf("gatedBundles() = %d bundles, want %d", len(bundles), tt.want)
+			}
+		})
+	}
+}
+
+func TestSendBundleGatesEveryBundle(t *testing.T) {
+	txs := rawTxs(t, signedTxs(t, 1))
+	transport := &fakeTransport{results: map[string]string{
+		_CallBundle: `{"coinbaseDiff":"100","results":[{"txHash":"0x0000000000000000000000000000000000000000000000000000000000000001"}]}`,
+		_SendBundle: `{"bundleHash":"0x01"}`,
+	}}
+	fbc := &FlashbotsClient{logger: zap.NewNop(), transport: transport}
+	fbc.SetBundleGate(&BundleGate{MinCoinbasePayment: big.NewInt(10)})
+	sink := &blockingSink{release: make(chan struct{})}
+	close(sink.release)
+	fbc.SetAuditSink(sink)
+
+	bundles := []common.SendBundleArgs{{Txs: txs}, {Txs: txs}, {Txs: txs}}
+	if _, err := fbc.SendBundle(context.Background(), bundles); err != nil {
+		t.Fatal(err)
+	}
+	if n := transport.count(_CallBundle); n != len(bundles) {
+		t.Fatalf("simulated %d bundles, want %d", n, len(bundles))
+	}
+
+	fbc.SetBundleGate(&BundleGate{MinCoinbasePayment: big.NewInt(1000)})
+	if _, err := fbc.SendBundle(context.Background(), bundles); !errors.Is(err, ErrBundleRejected) {
+		t.Fatalf("SendBundle() error = %v, want ErrBundleRejected", err)
+	}
+	if n := transport.count(_SendBundle); n != 1 {
+		t.Fatalf("submitted %d times, want 1", n)
+	}
+
+	// the gate simulations are recorded with the sends, not on their own
+	if err := fbc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	records := sink.records()
+	if len(records) != 2*len(bundles) {
+		t.Fatalf("recorded %d entries, want %d", len(records), 2*len(bundles))
+	}
+	for i, rec := range records {
+		if rec.Method != _SendBundle {
+			t.Fatalf("record %d method = %s, want %s", i, rec.Method, _SendBundle)
+		}
+	}
+}
+
+func TestSetBundleGateConcurrent(t *testing.T) {
+	fbc := &FlashbotsClient{logger: zap.NewNop()}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				fbc.SetBundleGate(&BundleGate{})
+				_ = fbc.bundleGate()
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 )
 
 type HttpClient struct {
@@ -21,6 +22,14 @@ type HttpClient struct {
 	mu      sync.Mutex // protects headers
 	headers http.Header
 	signer  Signer
+	retry   RetryPolicy
+	limiter *rate.Limiter
+
+	interceptors    []Interceptor
+	maxResponseSize int64
+	transportOpts   TransportOptions
+	resolver        *hostCache
+	observeTiming   func(RequestTiming)
 }
 
 func DialHttpClient(rawURL string) (*HttpClient, error) {
@@ -63,6 +72,28 @@ func DialHttpClientWithSingerKey(rawURL, privateKey string) (*HttpClient, error)
 	}, nil
 }
 
+// DialHttpClientWithSigner uses a custom signer. A nil signer sends requests without
+// the x-flashbots-signature header, as expected by public endpoints like Protect.
+func DialHttpClientWithSigner(rawURL string, signer Signer) (*HttpClient, error) {
+	_, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	headers := make(http.Header, 2)
+	headers.Set("accept", "application/json")
+	headers.Set("content-type", "application/json")
+	client := &http.Client{
+		Timeout: time.Second * 5,
+	}
+	return &HttpClient{
+		logger:  common.NewLogger(),
+		client:  client,
+		url:     rawURL,
+		headers: headers,
+		signer:  signer,
+	}, nil
+}
+
 func DialHttpClientWithLocalHost(rawURL string) (*HttpClient, error) {
 	return DialHttpClient("http://localhost:8080")
 }
@@ -76,14 +107,17 @@ func (hc *HttpClient) doRequest(ctx context.Context, msg interface{}) (io.ReadCl
 	}
 
 	// sign payload
-	signature, err := hc.signer.SignPayload(payload)
-	if err != nil {
-		hc.logger.Error("failed to sign payload", zap.Error(err))
-		return nil, err
+	var signature *string
+	if hc.signer != nil {
+		signature, err = hc.signer.SignPayload(payload)
+		if err != nil {
+			hc.logger.Error("failed to sign payload", zap.Error(err))
+			return nil, err
+		}
 	}
 
 	// create request
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, hc.url, io.NopCloser(bytes.NewReader(payload)))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, hc.url, bytes.NewReader(payload))
 	if err != nil {
 		return nil, err
 	}
@@ -91,41 +125,73 @@ func (hc *HttpClient) doRequest(ctx context.Context, msg interface{}) (io.ReadCl
 	// set headers
 	hc.mu.Lock()
 	request.Header = hc.headers.Clone()
-	request.Header.Set("x-flashbots-signature", *signature)
+	for key, values := range headerFromContext(ctx) {
+		if !protectedHeader(key) {
+			request.Header[http.CanonicalHeaderKey(key)] = values
+		}
+	}
+	if signature != nil {
+		request.Header.Set("x-flashbots-signature", *signature)
+	}
 	hc.mu.Unlock()
 
 	// send request
+	var timing func(*http.Response) RequestTiming
+	if hc.observeTiming != nil {
+		method, _ := rpcMethod(payload)
+		request, timing = traceRequest(request, method)
+	}
 	resp, err := hc.client.Do(request)
+	if timing != nil {
+		hc.observeTiming(timing(resp))
+	}
 	if err != nil {
 		return nil, err
 	}
+	if err = hc.checkProto(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
 
 	// handle response
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var buf bytes.Buffer
-		var _body []byte
-		if _, err = buf.ReadFrom(resp.Body); err == nil {
-			_body = buf.Bytes()
-		}
-
-		return nil, common.HTTPError{
-			Status:     resp.Status,
-			StatusCode: resp.StatusCode,
-			Body:       _body,
-		}
+		defer resp.Body.Close()
+		method, _ := rpcMethod(payload)
+		return nil, newHTTPError(resp, method)
 	}
-	return resp.Body, nil
+	if err = checkContentType(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return newLimitedBody(resp.Body, hc.responseLimit()), nil
 }
 
 func (hc *HttpClient) CallContext(ctx context.Context, msg interface{}) (*common.JSONRPCMessage, error) {
-	respBody, err := hc.doRequest(ctx, msg)
+	if len(hc.interceptors) == 0 {
+		return hc.invoke(ctx, msg)
+	}
+	req, err := toMessage(msg)
 	if err != nil {
 		return nil, err
 	}
+	return chain(hc.interceptors, func(ctx context.Context, req *common.JSONRPCMessage) (*common.JSONRPCMessage, error) {
+		return hc.invoke(ctx, req)
+	})(ctx, req)
+}
 
-	defer respBody.Close()
+func (hc *HttpClient) invoke(ctx context.Context, msg interface{}) (*common.JSONRPCMessage, error) {
 	var resp *common.JSONRPCMessage
-	if err = json.NewDecoder(respBody).Decode(&resp); err != nil {
+	err := hc.doWithRetry(ctx, func() error {
+		respBody, err := hc.doRequest(ctx, msg)
+		if err != nil {
+			return err
+		}
+
+		defer respBody.Close()
+		resp = nil
+		return json.NewDecoder(respBody).Decode(&resp)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return resp, nil
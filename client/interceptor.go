@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"go.uber.org/zap"
+)
+
+// Invoker sends a JSON-RPC request and returns the decoded response.
+type Invoker func(ctx context.Context, req *common.JSONRPCMessage) (*common.JSONRPCMessage, error)
+
+// Interceptor wraps every call of an HttpClient. It sees the request before it is signed
+// and the decoded response, and must call next to continue the chain. A retried call
+// passes through the interceptors once.
+type Interceptor func(ctx context.Context, req *common.JSONRPCMessage, next Invoker) (*common.JSONRPCMessage, error)
+
+// Use appends interceptors to the chain, the first one added being the outermost.
+// Not safe to call concurrently with requests.
+func (hc *HttpClient) Use(interceptors ...Interceptor) {
+	hc.interceptors = append(hc.interceptors, interceptors...)
+}
+
+// Use appends interceptors to the chain of the transport.
+func (fbc *FlashbotsClient) Use(interceptors ...Interceptor) {
+	fbc.transport.Use(interceptors...)
+}
+
+// chain builds the invoker running the interceptors in order before invoker.
+func chain(interceptors []Interceptor, invoker Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, req *common.JSONRPCMessage) (*common.JSONRPCMessage, error) {
+			return interceptor(ctx, req, next)
+		}
+	}
+	return invoker
+}
+
+// toMessage returns msg as a single JSON-RPC request.
+func toMessage(msg interface{}) (*common.JSONRPCMessage, error) {
+	switch m := msg.(type) {
+	case *common.JSONRPCMessage:
+		return m, nil
+	case common.JSONRPCMessage:
+		return &m, nil
+	}
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	req := new(common.JSONRPCMessage)
+	if err = json.Unmarshal(b, req); err != nil {
+		return nil, fmt.Errorf("not a json-rpc request: %w", err)
+	}
+	return req, nil
+}
+
+type headerKey struct{}
+
+// WithHeader returns a context adding key: value to the HTTP request sent by HttpClient.
+// Values added for the same key are all sent. Content-Type and the signature header
+// cannot be overridden.
+func WithHeader(ctx context.Context, key, value string) context.Context {
+	return withHeaders(ctx, http.Header{http.CanonicalHeaderKey(key): {value}})
+}
+
+// withHeaders returns a context adding every value of header to those of ctx.
+func withHeaders(ctx context.Context, header http.Header) context.Context {
+	merged := make(http.Header)
+	if parent, ok := ctx.Value(headerKey{}).(http.Header); ok {
+		merged = parent.Clone()
+	}
+	for key, values := range header {
+		for _, v := range values {
+			merged.Add(key, v)
+		}
+	}
+	return context.WithValue(ctx, headerKey{}, merged)
+}
+
+func headerFromContext(ctx context.Context) http.Header {
+	header, _ := ctx.Value(headerKey{}).(http.Header)
+	return header
+}
+
+// protectedHeader reports whether key is set by HttpClient itself and must not be
+// replaced by context headers.
+func protectedHeader(key string) bool {
+	switch http.CanonicalHeaderKey(key) {
+	case "Content-Type", http.CanonicalHeaderKey(signatureHeader):
+		return true
+	}
+	return false
+}
+
+// HeaderInterceptor adds the headers returned by fn to each request, e.g. an auth token
+// of a private builder. Unlike HttpClient.SetHeader the value may change per call.
+func HeaderInterceptor(fn func(ctx context.Context, method string) (http.Header, error)) Interceptor {
+	return func(ctx context.Context, req *common.JSONRPCMessage, next Invoker) (*common.JSONRPCMessage, error) {
+		header, err := fn(ctx, req.Method)
+		if err != nil {
+			return nil, err
+		}
+		if len(header) > 0 {
+			ctx = withHeaders(ctx, header)
+		}
+		return next(ctx, req)
+	}
+}
+
+// LoggingInterceptor logs the method, latency and outcome of every call at debug level,
+// failures at warn level. Params and results are left out as they hold signed transactions.
+func LoggingInterceptor(logger *zap.Logger) Interceptor {
+	return func(ctx context.Context, req *common.JSONRPCMessage, next Invoker) (*common.JSONRPCMessage, error) {
+		start := time.Now()
+		res, err := next(ctx, req)
+		fields := []zap.Field{zap.String("method", req.Method), zap.Duration("latency", time.Since(start))}
+		switch {
+		case err != nil:
+			logger.Warn("json-rpc call failed", append(fields, zap.Error(err))...)
+		case res != nil && res.Error != nil:
+			logger.Warn("json-rpc call returned an error", append(fields, zap.Int("code", res.Error.Code), zap.String("message", res.Error.Message))...)
+		default:
+			logger.Debug("json-rpc call", fields...)
+		}
+		return res, err
+	}
+}
+
+// Metrics receives one observation per call. err is the transport error or, when the
+// relay answered with a JSON-RPC error, that error.
+type Metrics interface {
+	ObserveCall(method string, latency time.Duration, err error)
+}
+
+func MetricsInterceptor(metrics Metrics) Interceptor {
+	return func(ctx context.Context, req *common.JSONRPCMessage, next Invoker) (*common.JSONRPCMessage, error) {
+		start := time.Now()
+		res, err := next(ctx, req)
+		observed := err
+		if observed == nil && res != nil && res.Error != nil {
+			observed = res.Error
+		}
+		metrics.ObserveCall(req.Method, time.Since(start), observed)
+		return res, err
+	}
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const testSignerKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+
+func TestContextHeaders(t *testing.T) {
+	received := make(chan http.Header, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x01"}`))
+	}))
+	defer srv.Close()
+
+	hc, err := DialHttpClientWithSingerKey(srv.URL, testSignerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hc.Use(HeaderInterceptor(func(ctx context.Context, method string) (http.Header, error) {
+		return http.Header{
+			"x-builder-auth": {"a", "b"},
+			"Content-Type":   {"text/plain"},
+		}, nil
+	}))
+
+	ctx := WithHeader(context.Background(), "X-Trace", "1")
+	ctx = WithHeader(ctx, "x-trace", "2")
+	ctx = WithHeader(ctx, signatureHeader, "forged")
+	if _, err := CallRaw(ctx, hc, "eth_blockNumber", []interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+	header := <-received
+
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"X-Trace", []string{"1", "2"}},
+		{"X-Builder-Auth", []string{"a", "b"}},
+		{"Content-Type", []string{"application/json"}},
+	}
+	for _, tt := range tests {
+		if got := header.Values(tt.key); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("header %s = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+	if sig := header.Values(signatureHeader); len(sig) != 1 || sig[0] == "forged" {
+		t.Errorf("signature header = %v", sig)
+	}
+}
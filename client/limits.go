@@ -0,0 +1,142 @@
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+)
+
+const (
+	// DefaultMaxResponseSize bounds the body of a successful response.
+	DefaultMaxResponseSize = 16 << 20
+	// errorPreviewSize bounds the body kept in common.HTTPError.
+	errorPreviewSize = 4 << 10
+)
+
+// ErrResponseTooLarge is returned when a response body exceeds the configured maximum size.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// SetMaxResponseSize bounds the size of successful response bodies, a value <= 0 restores
+// DefaultMaxResponseSize. Not safe to call concurrently with requests.
+func (hc *HttpClient) SetMaxResponseSize(n int64) {
+	hc.maxResponseSize = n
+}
+
+func (hc *HttpClient) responseLimit() int64 {
+	if hc.maxResponseSize <= 0 {
+		return DefaultMaxResponseSize
+	}
+	return hc.maxResponseSize
+}
+
+// limitedBody fails with ErrResponseTooLarge instead of silently truncating, so a
+// decoder reading from it never sees a partial document as complete.
+type limitedBody struct {
+	rc    io.ReadCloser
+	limit int64
+	read  int64
+}
+
+func newLimitedBody(rc io.ReadCloser, limit int64) *limitedBody {
+	return &limitedBody{rc: rc, limit: limit}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.read > b.limit {
+		return 0, fmt.Errorf("%w: exceeds %d bytes", ErrResponseTooLarge, b.limit)
+	}
+	// read one byte past the limit to tell an exact fit from an overflow
+	if max := b.limit - b.read + 1; int64(len(p)) > max {
+		p = p[:max]
+	}
+	n, err := b.rc.Read(p)
+	b.read += int64(n)
+	if b.read > b.limit {
+		return n, fmt.Errorf("%w: exceeds %d bytes", ErrResponseTooLarge, b.limit)
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.rc.Close()
+}
+
+// readPreview reads at most n bytes of r and reports whether more were available.
+func readPreview(r io.Reader, n int64) ([]byte, bool) {
+	b, _ := io.ReadAll(io.LimitReader(r, n+1))
+	if int64(len(b)) > n {
+		return b[:n], true
+	}
+	return b, false
+}
+
+// checkContentType accepts JSON media types and responses without a content type.
+// text/plain is accepted when the body starts like JSON, as Go servers that do not set
+// a content type sniff JSON as text/plain.
+func checkContentType(resp *http.Response) error {
+	contentType := resp.Header.Get("content-type")
+	if contentType == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) {
+		return nil
+	}
+	if err == nil && mediaType == "text/plain" && looksLikeJSON(resp) {
+		return nil
+	}
+	preview, truncated := readPreview(resp.Body, errorPreviewSize)
+	return &common.ContentTypeError{
+		ContentType: contentType,
+		StatusCode:  resp.StatusCode,
+		Body:        preview,
+		Truncated:   truncated,
+	}
+}
+
+// looksLikeJSON peeks at the first non-space byte of the body without consuming it.
+func looksLikeJSON(resp *http.Response) bool {
+	br := bufio.NewReader(resp.Body)
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{br, resp.Body}
+	for i := 1; ; i++ {
+		b, err := br.Peek(i)
+		if err != nil {
+			return false
+		}
+		switch b[i-1] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '{', '[':
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// newHTTPError reads a preview of the body of the non-2xx resp.
+func newHTTPError(resp *http.Response, method string) common.HTTPError {
+	body, truncated := readPreview(resp.Body, errorPreviewSize)
+	httpErr := common.HTTPError{
+		Method:     method,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header,
+		Body:       body,
+		Truncated:  truncated,
+	}
+	if resp.Request != nil {
+		httpErr.URL = resp.Request.URL.Redacted()
+	}
+	httpErr.DecodeRPCError()
+	return httpErr
+}
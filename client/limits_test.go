@@ -0,0 +1,253 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+)
+
+func TestResponseLimits(t *testing.T) {
+	const result = `{"jsonrpc":"2.0","id":1,"result":"ok"}`
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		limit       int64
+		check       func(t *testing.T, resp *common.JSONRPCMessage, err error)
+	}{
+		{
+			name:        "json",
+			status:      http.StatusOK,
+			contentType: "application/json; charset=utf-8",
+			body:        result,
+			check:       wantResult,
+		},
+		{
+			name:        "exact fit",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        result,
+			limit:       int64(len(result)),
+			check:       wantResult,
+		},
+		{
+			name:        "oversize success body",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"jsonrpc":"2.0","id":1,"result":"` + strings.Repeat("a", 1024) + `"}`,
+			limit:       512,
+			check: func(t *testing.T, resp *common.JSONRPCMessage, err error) {
+				if !errors.Is(err, ErrResponseTooLarge) {
+					t.Fatalf("CallContext() = %v, %v, want ErrResponseTooLarge", resp, err)
+				}
+			},
+		},
+		{
+			name:        "oversize error body",
+			status:      http.StatusInternalServerError,
+			contentType: "application/json",
+			body:        strings.Repeat("e", 2*errorPreviewSize),
+			check: func(t *testing.T, resp *common.JSONRPCMessage, err error) {
+				var httpErr common.HTTPError
+				if !errors.As(err, &httpErr) {
+					t.Fatalf("CallContext() error = %v, want HTTPError", err)
+				}
+				if httpErr.StatusCode != http.StatusInternalServerError || !httpErr.Truncated || len(httpErr.Body) != errorPreviewSize {
+					t.Fatalf("HTTPError = %d, truncated %v, %d body bytes", httpErr.StatusCode, httpErr.Truncated, len(httpErr.Body))
+				}
+				if !strings.HasSuffix(err.Error(), "...(truncated)") {
+					t.Fatalf("Error() = %q, want truncation marker", err.Error())
+				}
+			},
+		},
+		{
+			name:        "html 502",
+			status:      http.StatusBadGateway,
+			contentType: "text/html",
+			body:        "<html><body><h1>502 Bad Gateway</h1></body></html>",
+			check: func(t *testing.T, resp *common.JSONRPCMessage, err error) {
+				var httpErr common.HTTPError
+				if !errors.As(err, &httpErr) {
+					t.Fatalf("CallContext() error = %v, want HTTPError", err)
+				}
+				if httpErr.StatusCode != http.StatusBadGateway || httpErr.Truncated || !strings.HasPrefix(string(httpErr.Body), "<html>") {
+					t.Fatalf("HTTPError = %+v", httpErr)
+				}
+			},
+		},
+		{
+			name:        "html 200",
+			status:      http.StatusOK,
+			contentType: "text/html",
+			body:        "<html><body>maintenance</body></html>",
+			check: func(t *testing.T, resp *common.JSONRPCMessage, err error) {
+				var ctErr *common.ContentTypeError
+				if !errors.As(err, &ctErr) {
+					t.Fatalf("CallContext() error = %v, want ContentTypeError", err)
+				}
+				if ctErr.ContentType != "text/html" || string(ctErr.Body) != "<html><body>maintenance</body></html>" {
+					t.Fatalf("ContentTypeError = %+v", ctErr)
+				}
+			},
+		},
+		{
+			name:        "json as text/plain",
+			status:      http.StatusOK,
+			contentType: "text/plain; charset=utf-8",
+			body:        "\n  " + result,
+			check:       wantResult,
+		},
+		{
+			name:        "text/plain",
+			status:      http.StatusOK,
+			contentType: "text/plain",
+			body:        "rate limited",
+			check: func(t *testing.T, resp *common.JSONRPCMessage, err error) {
+				var ctErr *common.ContentTypeError
+				if !errors.As(err, &ctErr) || string(ctErr.Body) != "rate limited" {
+					t.Fatalf("CallContext() error = %v, want ContentTypeError", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			hc, err := DialHttpClientWithSigner(srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hc.SetMaxResponseSize(tt.limit)
+			resp, err := hc.CallContext(context.Background(), common.NewJSONRPCMessage("eth_blockNumber", nil))
+			tt.check(t, resp, err)
+		})
+	}
+}
+
+func wantResult(t *testing.T, resp *common.JSONRPCMessage, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.Result) != `"ok"` {
+		t.Fatalf("result = %s, want \"ok\"", resp.Result)
+	}
+}
+
+func TestNewHTTPError(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		header        map[string]string
+		body          string
+		wantRPCCode   int
+		wantAttempts  int
+		wantRetryWait time.Duration
+	}{
+		{
+			name:         "400 with json-rpc error",
+			status:       http.StatusBadRequest,
+			body:         `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"bundle already known"}}`,
+			wantRPCCode:  -32000,
+			wantAttempts: 1,
+		},
+		{
+			name:          "429 retry after seconds",
+			status:        http.StatusTooManyRequests,
+			header:        map[string]string{"Retry-After": "1"},
+			wantAttempts:  2,
+			wantRetryWait: time.Second,
+		},
+		{
+			name:         "500",
+			status:       http.StatusInternalServerError,
+			body:         "upstream failed",
+			wantAttempts: 2,
+		},
+		{
+			name:         "500 with json-rpc error",
+			status:       http.StatusInternalServerError,
+			body:         `{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"internal error"}}`,
+			wantRPCCode:  -32603,
+			wantAttempts: 1,
+		},
+		{
+			name:         "html 502",
+			status:       http.StatusBadGateway,
+			header:       map[string]string{"Content-Type": "text/html"},
+			body:         "<html>502</html>",
+			wantAttempts: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			u, err := url.Parse(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			u.User = url.UserPassword("user", "secret")
+			hc, err := DialHttpClientWithSigner(u.String(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hc.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, MaxBackoff: 2 * time.Second})
+			start := time.Now()
+			_, err = hc.CallContext(context.Background(), common.NewJSONRPCMessage("eth_sendBundle", nil))
+			elapsed := time.Since(start)
+
+			var httpErr common.HTTPError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("CallContext() error = %v, want HTTPError", err)
+			}
+			if httpErr.Method != "eth_sendBundle" || httpErr.StatusCode != tt.status {
+				t.Fatalf("HTTPError method %q status %d", httpErr.Method, httpErr.StatusCode)
+			}
+			if strings.Contains(httpErr.URL, "secret") || !strings.HasPrefix(httpErr.URL, "http://user:") {
+				t.Fatalf("HTTPError URL = %q, want the password redacted", httpErr.URL)
+			}
+			for k, v := range tt.header {
+				if httpErr.Header.Get(k) != v {
+					t.Fatalf("HTTPError header %s = %q, want %q", k, httpErr.Header.Get(k), v)
+				}
+			}
+			if tt.wantRPCCode != 0 && (httpErr.RPCError == nil || httpErr.RPCError.Code != tt.wantRPCCode) {
+				t.Fatalf("RPCError = %v, want code %d", httpErr.RPCError, tt.wantRPCCode)
+			}
+			if tt.wantRPCCode == 0 && httpErr.RPCError != nil {
+				t.Fatalf("RPCError = %v, want nil", httpErr.RPCError)
+			}
+			if n := atomic.LoadInt32(&attempts); int(n) != tt.wantAttempts {
+				t.Fatalf("%d attempts, want %d", n, tt.wantAttempts)
+			}
+			if elapsed < tt.wantRetryWait {
+				t.Fatalf("retried after %v, want at least %v", elapsed, tt.wantRetryWait)
+			}
+		})
+	}
+}
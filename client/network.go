@@ -0,0 +1,186 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+)
+
+// NewFlashbotsClientForNetwork connects to the relay of a registered network and rejects
+// transactions signed for another chain. An empty signerKey falls back to $SIGNER_PRIVATE_KEY.
+func NewFlashbotsClientForNetwork(name, signerKey string) (*FlashbotsClient, error) {
+	network, err := common.LookupNetwork(name)
+	if err != nil {
+		return nil, err
+	}
+	var fbc *FlashbotsClient
+	if signerKey == "" {
+		fbc = NewFlashbotsClient(network.RelayURL)
+	} else {
+		fbc = NewFlashbotsClientWithSigner(network.RelayURL, signerKey)
+	}
+	fbc.SetNetwork(&network)
+	return fbc, nil
+}
+
+// SetNetwork enables chain id checks of the txs passed to CallBundle, SendBundle and the
+// private transaction methods. A nil network disables them.
+func (fbc *FlashbotsClient) SetNetwork(network *common.Network) {
+	fbc.mu.Lock()
+	defer fbc.mu.Unlock()
+	fbc.network = network
+}
+
+func (fbc *FlashbotsClient) chainNetwork() *common.Network {
+	fbc.mu.RLock()
+	defer fbc.mu.RUnlock()
+	return fbc.network
+}
+
+// checkTxs rejects tx types that cannot be bundled and, when a network is set, txs
+// signed for another chain. Arguments whose txs cannot be found are rejected while a
+// network is set rather than sent unchecked.
+func (fbc *FlashbotsClient) checkTxs(arg interface{}) error {
+	rawTxs, ok := rawTxsOf(arg)
+	network := fbc.chainNetwork()
+	if network == nil {
+		return common.CheckTxTypes(rawTxs)
+	}
+	if !ok {
+		return fmt.Errorf("network %s: cannot find the txs of argument of type %T", network.Name, arg)
+	}
+	return network.CheckTxs(rawTxs)
+}
+
+// rawTxsOf collects the raw txs of arg. Types other than the argument types of this
+// package, e.g. maps or raw JSON, are inspected through their JSON encoding. ok is false
+// when no txs field could be found.
+func rawTxsOf(arg interface{}) (txs []string, ok bool) {
+	switch a := arg.(type) {
+	case common.CallBundleArgs:
+		return a.Txs, true
+	case *common.CallBundleArgs:
+		if a != nil {
+			txs = a.Txs
+		}
+		return txs, true
+	case []common.CallBundleArgs:
+		for _, v := range a {
+			txs = append(txs, v.Txs...)
+		}
+		return txs, true
+	case []*common.CallBundleArgs:
+		for _, v := range a {
+			if v != nil {
+				txs = append(txs, v.Txs...)
+			}
+		}
+		return txs, true
+	case common.SendBundleArgs:
+		return a.Txs, true
+	case *common.SendBundleArgs:
+		if a != nil {
+			txs = a.Txs
+		}
+		return txs, true
+	case []common.SendBundleArgs:
+		for _, v := range a {
+			txs = append(txs, v.Txs...)
+		}
+		return txs, true
+	case []*common.SendBundleArgs:
+		for _, v := range a {
+			if v != nil {
+				txs = append(txs, v.Txs...)
+			}
+		}
+		return txs, true
+	case common.SendPrivateTxArgs:
+		return []string{a.Tx}, true
+	case *common.SendPrivateTxArgs:
+		if a != nil {
+			txs = []string{a.Tx}
+		}
+		return txs, true
+	case []common.SendPrivateTxArgs:
+		for _, v := range a {
+			txs = append(txs, v.Tx)
+		}
+		return txs, true
+	case []*common.SendPrivateTxArgs:
+		for _, v := range a {
+			if v != nil {
+				txs = append(txs, v.Tx)
+			}
+		}
+		return txs, true
+	case common.SendPrivateRawTxArgs:
+		return []string{a.Tx}, true
+	case *common.SendPrivateRawTxArgs:
+		if a != nil {
+			txs = []string{a.Tx}
+		}
+		return txs, true
+	case nil:
+		return nil, true
+	}
+
+	var b []byte
+	switch a := arg.(type) {
+	case json.RawMessage:
+		b = a
+	case []byte:
+		b = a
+	default:
+		var err error
+		if b, err = json.Marshal(arg); err != nil {
+			return nil, false
+		}
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, false
+	}
+	ok = collectRawTxs(v, &txs)
+	return txs, ok
+}
+
+// collectRawTxs walks decoded params: objects with a txs or tx field, arrays of them, and
+// the positional [tx, preferences] params of eth_sendPrivateRawTransaction.
+func collectRawTxs(v interface{}, txs *[]string) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		found := false
+		if list, ok := v["txs"].([]interface{}); ok {
+			for _, tx := range list {
+				s, ok := tx.(string)
+				if !ok {
+					return false
+				}
+				*txs = append(*txs, s)
+			}
+			found = true
+		}
+		if tx, ok := v["tx"].(string); ok {
+			*txs = append(*txs, tx)
+			found = true
+		}
+		return found
+	case []interface{}:
+		if len(v) == 0 {
+			return false
+		}
+		if tx, ok := v[0].(string); ok {
+			*txs = append(*txs, tx)
+			return true
+		}
+		for _, elem := range v {
+			if !collectRawTxs(elem, txs) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
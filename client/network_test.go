@@ -0,0 +1,85 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"go.uber.org/zap"
+)
+
+func TestCheckTxsArgTypes(t *testing.T) {
+	// signed for mainnet, so every form must fail the sepolia chain id check
+	tx := rawTxs(t, signedTxs(t, 1))[0]
+	txs := []string{tx}
+	call := common.CallBundleArgs{Txs: txs}
+	send := common.SendBundleArgs{Txs: txs}
+	private := common.SendPrivateTxArgs{Tx: tx}
+	privateRaw := common.SendPrivateRawTxArgs{Tx: tx}
+	rawJSON, _ := json.Marshal([]common.SendBundleArgs{send})
+
+	tests := []struct {
+		name string
+		arg  interface{}
+	}{
+		{"call bundle", call},
+		{"call bundle pointer", &call},
+		{"call bundle slice", []common.CallBundleArgs{call}},
+		{"call bundle pointer slice", []*common.CallBundleArgs{&call}},
+		{"send bundle", send},
+		{"send bundle pointer", &send},
+		{"send bundle slice", []common.SendBundleArgs{send}},
+		{"send bundle pointer slice", []*common.SendBundleArgs{&send}},
+		{"private tx", private},
+		{"private tx pointer", &private},
+		{"private tx slice", []common.SendPrivateTxArgs{private}},
+		{"private tx pointer slice", []*common.SendPrivateTxArgs{&private}},
+		{"private raw tx", privateRaw},
+		{"private raw tx pointer", &privateRaw},
+		{"map", map[string]interface{}{"txs": txs, "blockNumber": "0x1"}},
+		{"map slice", []map[string]interface{}{{"tx": tx}}},
+		{"raw json", json.RawMessage(rawJSON)},
+		{"bytes", rawJSON},
+		{"positional params", []interface{}{tx, map[string]interface{}{"fast": true}}},
+	}
+
+	sepolia, err := common.LookupNetwork(common.NetworkSepolia)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mainnet, err := common.LookupNetwork(common.NetworkMainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fbc := &FlashbotsClient{logger: zap.NewNop()}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fbc.SetNetwork(&sepolia)
+			var mismatch *common.ChainIDMismatchError
+			if err := fbc.checkTxs(tt.arg); !errors.As(err, &mismatch) {
+				t.Fatalf("checkTxs() error = %v, want chain id mismatch", err)
+			}
+			fbc.SetNetwork(&mainnet)
+			if err := fbc.checkTxs(tt.arg); err != nil {
+				t.Fatalf("checkTxs() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckTxsUnknownArg(t *testing.T) {
+	arg := struct{ Bundle []string }{[]string{"0x01"}}
+	fbc := &FlashbotsClient{logger: zap.NewNop()}
+	if err := fbc.checkTxs(arg); err != nil {
+		t.Fatalf("checkTxs() without network error = %v", err)
+	}
+	mainnet, err := common.LookupNetwork(common.NetworkMainnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fbc.SetNetwork(&mainnet)
+	if err := fbc.checkTxs(arg); err == nil {
+		t.Fatal("checkTxs() accepted an argument without txs")
+	}
+}
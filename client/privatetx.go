@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// defaultMaxBlockOffset mirrors the relay, which keeps a private tx for 25 blocks
+// when maxBlockNumber is not given.
+const defaultMaxBlockOffset = 25
+
+// TxChainReader extends ChainReader with receipt lookups.
+type TxChainReader interface {
+	ChainReader
+	TransactionReceipt(ctx context.Context, txHash gethcommon.Hash) (*types.Receipt, error)
+}
+
+type PrivateTxStatus int
+
+const (
+	PrivateTxPending PrivateTxStatus = iota
+	PrivateTxMined
+	PrivateTxExpired
+	PrivateTxCancelled
+)
+
+func (s PrivateTxStatus) String() string {
+	switch s {
+	case PrivateTxMined:
+		return "mined"
+	case PrivateTxExpired:
+		return "expired"
+	case PrivateTxCancelled:
+		return "cancelled"
+	default:
+		return "pending"
+	}
+}
+
+// PrivateTxResult is the final status of a watched private transaction.
+type PrivateTxResult struct {
+	TxHash         gethcommon.Hash
+	Status         PrivateTxStatus
+	MaxBlockNumber uint64
+	// BlockNumber is the block the tx was mined in, or the head at which watching stopped.
+	BlockNumber uint64
+	Receipt     *types.Receipt
+}
+
+// CancelCondition is evaluated on every new head; returning true cancels the private tx.
+type CancelCondition func(ctx context.Context, head *types.Header) (bool, error)
+
+// CancelAfter triggers once the wall clock passes deadline.
+func CancelAfter(deadline time.Time) CancelCondition {
+	return func(_ context.Context, _ *types.Header) (bool, error) {
+		return time.Now().After(deadline), nil
+	}
+}
+
+// PrivateTxWatcher follows a private transaction until it is mined, expires or is cancelled.
+type PrivateTxWatcher struct {
+	logger       *zap.Logger
+	fbc          *FlashbotsClient
+	chain        TxChainReader
+	pollInterval time.Duration
+}
+
+func NewPrivateTxWatcher(fbc *FlashbotsClient, chain TxChainReader) *PrivateTxWatcher {
+	return &PrivateTxWatcher{
+		logger:       common.NewLogger(),
+		fbc:          fbc,
+		chain:        chain,
+		pollInterval: time.Second * 2,
+	}
+}
+
+// SendAndWatch submits arg with SendPrivateTransaction and watches it until a final status is known.
+// cond may be nil.
+func (w *PrivateTxWatcher) SendAndWatch(ctx context.Context, arg common.SendPrivateTxArgs, cond CancelCondition) (*PrivateTxResult, error) {
+	var maxBlockNumber uint64
+	if arg.MaxBlockNumber != "" {
+		n, err := hexutil.DecodeUint64(arg.MaxBlockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maxBlockNumber %q: %w", arg.MaxBlockNumber, err)
+		}
+		maxBlockNumber = n
+	}
+	res, err := w.fbc.SendPrivateTransaction(ctx, []common.SendPrivateTxArgs{arg})
+	if err != nil {
+		return nil, err
+	}
+	if maxBlockNumber == 0 {
+		head, err := w.chain.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		maxBlockNumber = head.Number.Uint64() + defaultMaxBlockOffset
+	}
+	return w.Watch(ctx, gethcommon.HexToHash(res.TxHash), maxBlockNumber, cond)
+}
+
+// Watch blocks until txHash is mined, maxBlockNumber passes, or cond triggers and the
+// relay confirms the cancellation. cond may be nil.
+func (w *PrivateTxWatcher) Watch(ctx context.Context, txHash gethcommon.Hash, maxBlockNumber uint64, cond CancelCondition) (*PrivateTxResult, error) {
+	result := &PrivateTxResult{
+		TxHash:         txHash,
+		Status:         PrivateTxPending,
+		MaxBlockNumber: maxBlockNumber,
+	}
+	cancelTried := false
+	errDone := errors.New("done")
+
+	err := followHeads(ctx, w.logger, w.chain, w.pollInterval, func(head *types.Header) error {
+		result.BlockNumber = head.Number.Uint64()
+
+		receipt, err := w.chain.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			result.Status = PrivateTxMined
+			result.BlockNumber = receipt.BlockNumber.Uint64()
+			result.Receipt = receipt
+			return errDone
+		}
+		receiptFailed := !errors.Is(err, ethereum.NotFound)
+		if receiptFailed {
+			w.logger.Warn("failed to get tx receipt", zap.String("txHash", txHash.Hex()), zap.Error(err))
+		}
+
+		// checked whatever the receipt error, a failing node must not keep the watch alive
+		if result.BlockNumber >= maxBlockNumber {
+			result.Status = PrivateTxExpired
+			return errDone
+		}
+		if receiptFailed {
+			return nil
+		}
+
+		if cond == nil || cancelTried {
+			return nil
+		}
+		trigger, err := cond(ctx, head)
+		if err != nil {
+			w.logger.Warn("cancel condition failed", zap.String("txHash", txHash.Hex()), zap.Error(err))
+			return nil
+		}
+		if !trigger {
+			return nil
+		}
+		res, err := w.fbc.CancelPrivateTransaction(ctx, []common.CancelPrivateTxArgs{{TxHash: txHash.Hex()}})
+		if err != nil {
+			// retried on the next head
+			w.logger.Error("failed to cancel private tx", zap.String("txHash", txHash.Hex()), zap.Error(err))
+			return nil
+		}
+		// the relay answered, a refusal is final
+		cancelTried = true
+		if res.IsCancelled {
+			result.Status = PrivateTxCancelled
+			return errDone
+		}
+		return nil
+	})
+	if errors.Is(err, errDone) {
+		return result, nil
+	}
+	return result, err
+}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// fakeTxChain mines the watched tx at minedAt, never when zero. A non-nil receiptErr
+// fails every receipt lookup.
+type fakeTxChain struct {
+	fakeChain
+	minedAt    uint64
+	receiptErr error
+}
+
+func (c *fakeTxChain) TransactionReceipt(ctx context.Context, txHash gethcommon.Hash) (*types.Receipt, error) {
+	if c.receiptErr != nil {
+		return nil, c.receiptErr
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.minedAt == 0 || c.head < c.minedAt {
+		return nil, ethereum.NotFound
+	}
+	return &types.Receipt{TxHash: txHash, BlockNumber: new(big.Int).SetUint64(c.minedAt)}, nil
+}
+
+func cancelWhen(trigger bool) CancelCondition {
+	return func(context.Context, *types.Header) (bool, error) {
+		return trigger, nil
+	}
+}
+
+func newTestWatcher(transport Transport, chain TxChainReader) *PrivateTxWatcher {
+	w := NewPrivateTxWatcher(&FlashbotsClient{logger: zap.NewNop(), transport: transport}, chain)
+	w.logger = zap.NewNop()
+	w.pollInterval = time.Millisecond
+	return w
+}
+
+func TestPrivateTxWatcher(t *testing.T) {
+	txHash := gethcommon.HexToHash("0x01")
+	tests := []struct {
+		name        string
+		minedAt     uint64
+		receiptErr  error
+		cond        CancelCondition
+		cancel      string // eth_cancelPrivateTransaction result, the request fails when empty
+		wantStatus  PrivateTxStatus
+		wantBlock   uint64
+		wantCancels int
+	}{
+		{name: "mined", minedAt: 3, wantStatus: PrivateTxMined, wantBlock: 3},
+		{name: "expired", wantStatus: PrivateTxExpired, wantBlock: 5},
+		{name: "persistent receipt error", receiptErr: errors.New("node unavailable"), wantStatus: PrivateTxExpired, wantBlock: 5},
+		{name: "condition not met", cond: cancelWhen(false), cancel: "true", wantStatus: PrivateTxExpired, wantBlock: 5},
+		{name: "cancel success", cond: cancelWhen(true), cancel: "true", wantStatus: PrivateTxCancelled, wantBlock: 1, wantCancels: 1},
+		{name: "cancel refusal", cond: cancelWhen(true), cancel: "false", wantStatus: PrivateTxExpired, wantBlock: 5, wantCancels: 1},
+		{name: "cancel request failure", cond: cancelWhen(true), wantStatus: PrivateTxExpired, wantBlock: 5, wantCancels: 4},
+		{name: "mined after refusal", minedAt: 3, cond: cancelWhen(true), cancel: "false", wantStatus: PrivateTxMined, wantBlock: 3, wantCancels: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &fakeTransport{results: map[string]string{}}
+			if tt.cancel != "" {
+				transport.results[_CancelPrivateTx] = tt.cancel
+			}
+			chain := &fakeTxChain{minedAt: tt.minedAt, receiptErr: tt.receiptErr}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			res, err := newTestWatcher(transport, chain).Watch(ctx, txHash, 5, tt.cond)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Status != tt.wantStatus || res.BlockNumber != tt.wantBlock || res.TxHash != txHash || res.MaxBlockNumber != 5 {
+				t.Fatalf("Watch() = %+v, want %s at block %d", res, tt.wantStatus, tt.wantBlock)
+			}
+			if (res.Receipt != nil) != (tt.wantStatus == PrivateTxMined) {
+				t.Fatalf("Receipt = %v", res.Receipt)
+			}
+			if n := transport.count(_CancelPrivateTx); n != tt.wantCancels {
+				t.Fatalf("cancelled %d times, want %d", n, tt.wantCancels)
+			}
+		})
+	}
+}
+
+func TestSendAndWatch(t *testing.T) {
+	txHash := "0x0000000000000000000000000000000000000000000000000000000000000abc"
+	tests := []struct {
+		name           string
+		maxBlockNumber string
+		minedAt        uint64
+		cond           CancelCondition
+		cancel         string
+		wantStatus     PrivateTxStatus
+		wantMaxBlock   uint64
+		wantErr        bool
+	}{
+		{name: "expired", maxBlockNumber: "0x3", wantStatus: PrivateTxExpired, wantMaxBlock: 3},
+		{name: "relay default max block", minedAt: 4, wantStatus: PrivateTxMined, wantMaxBlock: 1 + defaultMaxBlockOffset},
+		{name: "cancel success", maxBlockNumber: "0x3", cond: cancelWhen(true), cancel: "true", wantStatus: PrivateTxCancelled, wantMaxBlock: 3},
+		{name: "cancel refusal", maxBlockNumber: "0x3", cond: cancelWhen(true), cancel: "false", wantStatus: PrivateTxExpired, wantMaxBlock: 3},
+		{name: "invalid max block", maxBlockNumber: "3", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &fakeTransport{results: map[string]string{_SendPrivateTx: `"` + txHash + `"`}}
+			if tt.cancel != "" {
+				transport.results[_CancelPrivateTx] = tt.cancel
+			}
+			chain := &fakeTxChain{minedAt: tt.minedAt}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			arg := common.SendPrivateTxArgs{Tx: "0x01", MaxBlockNumber: tt.maxBlockNumber}
+			res, err := newTestWatcher(transport, chain).SendAndWatch(ctx, arg, tt.cond)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendAndWatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if n := transport.count(_SendPrivateTx); n != 0 {
+					t.Fatalf("sent %d times, want 0", n)
+				}
+				return
+			}
+			if res.Status != tt.wantStatus || res.MaxBlockNumber != tt.wantMaxBlock || res.TxHash != gethcommon.HexToHash(txHash) {
+				t.Fatalf("SendAndWatch() = %+v, want %s with max block %d", res, tt.wantStatus, tt.wantMaxBlock)
+			}
+			if n := transport.count(_SendPrivateTx); n != 1 {
+				t.Fatalf("sent %d times, want 1", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+)
+
+const (
+	_SendRawTx = "eth_sendRawTransaction"
+
+	DefaultProtectURL       = "https://rpc.flashbots.net"
+	DefaultProtectStatusURL = "https://protect.flashbots.net/tx/"
+)
+
+// ProtectClient submits transactions to the Flashbots Protect RPC endpoint, which takes
+// a plain eth_sendRawTransaction and reads its preferences from the query string.
+type ProtectClient struct {
+	httpClient *HttpClient
+}
+
+func NewProtectClient(rawURL string, prefs *common.Preferences) (*ProtectClient, error) {
+	if err := prefs.Validate(); err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if prefs != nil && prefs.Fast {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/fast"
+	}
+	q := u.Query()
+	for k, v := range prefs.ProtectQuery() {
+		q[k] = append(q[k], v...)
+	}
+	u.RawQuery = q.Encode()
+
+	httpClient, err := DialHttpClientWithSigner(u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ProtectClient{httpClient: httpClient}, nil
+}
+
+func (pc *ProtectClient) SendRawTransaction(ctx context.Context, rawTx string) (*common.SendPrivateTransactionResponse, error) {
+	txHash, err := Call[[]string, string](ctx, pc.httpClient, _SendRawTx, []string{rawTx})
+	if err != nil {
+		return nil, err
+	}
+	return &common.SendPrivateTransactionResponse{TxHash: *txHash}, nil
+}
+
+// TxStatusClient queries the Flashbots Protect transaction status API.
+type TxStatusClient struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewTxStatusClient(baseURL string) *TxStatusClient {
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	return &TxStatusClient{
+		client: &http.Client{
+			Timeout: time.Second * 5,
+		},
+		baseURL: baseURL,
+	}
+}
+
+func (sc *TxStatusClient) Status(ctx context.Context, txHash string) (*common.TxStatusResponse, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, sc.baseURL+txHash, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("accept", "application/json")
+	resp, err := sc.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newHTTPError(resp, "")
+	}
+	if err = checkContentType(resp); err != nil {
+		return nil, err
+	}
+	var status *common.TxStatusResponse
+	if err = json.NewDecoder(newLimitedBody(resp.Body, DefaultMaxResponseSize)).Decode(&status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
@@ -0,0 +1,243 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// rpcServer answers every JSON-RPC request with result and keeps the last request.
+type rpcServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	request *http.Request
+	message common.JSONRPCMessage
+}
+
+func newRPCServer(t *testing.T, result string) *rpcServer {
+	t.Helper()
+	s := &rpcServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		var msg common.JSONRPCMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Error(err)
+		}
+		s.mu.Lock()
+		s.request, s.message = r, msg
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + result + `}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *rpcServer) last() (*http.Request, common.JSONRPCMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.request, s.message
+}
+
+func TestSendPrivateRawTransaction(t *testing.T) {
+	const txHash = "0x0000000000000000000000000000000000000000000000000000000000000abc"
+	rawTx := rawTxs(t, signedTxs(t, 1))[0]
+	tests := []struct {
+		name       string
+		arg        interface{}
+		wantParams string
+	}{
+		{
+			name:       "without preferences",
+			arg:        common.SendPrivateRawTxArgs{Tx: rawTx},
+			wantParams: `["` + rawTx + `"]`,
+		},
+		{
+			name: "with preferences",
+			arg: &common.SendPrivateRawTxArgs{Tx: rawTx, Preferences: &common.Preferences{
+				Fast:    true,
+				Privacy: &common.PrivacyPreferences{Hints: []common.Hint{common.HintHash}},
+			}},
+			wantParams: `["` + rawTx + `",{"fast":true,"privacy":{"hints":["hash"]}}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newRPCServer(t, `"`+txHash+`"`)
+			fbc := NewFlashbotsClientWithSigner(srv.URL, testSignerKey)
+
+			res, err := fbc.SendPrivateRawTransaction(context.Background(), tt.arg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.TxHash != txHash {
+				t.Fatalf("TxHash = %s, want %s", res.TxHash, txHash)
+			}
+			req, msg := srv.last()
+			if msg.Method != _SendPrivateRawTx || string(msg.Params) != tt.wantParams {
+				t.Fatalf("request %s %s, want %s %s", msg.Method, msg.Params, _SendPrivateRawTx, tt.wantParams)
+			}
+			if sig := req.Header.Get("x-flashbots-signature"); !strings.HasPrefix(sig, "0x") || !strings.Contains(sig, ":") {
+				t.Fatalf("x-flashbots-signature = %q", sig)
+			}
+		})
+	}
+	fbc := NewFlashbotsClientWithSigner(newRPCServer(t, `"`+txHash+`"`).URL, testSignerKey)
+	if _, err := fbc.SendPrivateRawTransaction(context.Background(), common.SendPrivateRawTxArgs{}); err == nil {
+		t.Fatal("SendPrivateRawTransaction() accepted an empty tx")
+	}
+}
+
+func TestProtectClient(t *testing.T) {
+	const txHash = "0x0000000000000000000000000000000000000000000000000000000000000abc"
+	rawTx := rawTxs(t, signedTxs(t, 1))[0]
+	refund := gethcommon.HexToAddress("0x00000000000000000000000000000000000000aa")
+	tests := []struct {
+		name      string
+		prefs     *common.Preferences
+		wantPath  string
+		wantQuery url.Values
+		wantErr   bool
+	}{
+		{name: "default", wantPath: "/", wantQuery: url.Values{}},
+		{
+			name: "preferences",
+			prefs: &common.Preferences{
+				Fast:       true,
+				Privacy:    &common.PrivacyPreferences{Hints: []common.Hint{common.HintCalldata, common.HintHash}, Builders: []string{common.BuilderFlashbots}},
+				Validity:   &common.ValidityPreferences{Refund: []common.RefundConfig{{Address: refund, Percent: 50}}},
+				OriginID:   "bot",
+				UseMempool: true,
+			},
+			wantPath: "/fast",
+			wantQuery: url.Values{
+				"hint":       {"calldata", "hash"},
+				"builder":    {"flashbots"},
+				"refund":     {refund.Hex() + ":50"},
+				"originId":   {"bot"},
+				"useMempool": {"true"},
+			},
+		},
+		{name: "invalid preferences", prefs: &common.Preferences{MempoolRPC: "https://rpc.example.org"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newRPCServer(t, `"`+txHash+`"`)
+			pc, err := NewProtectClient(srv.URL+"/", tt.prefs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewProtectClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			res, err := pc.SendRawTransaction(context.Background(), rawTx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.TxHash != txHash {
+				t.Fatalf("TxHash = %s, want %s", res.TxHash, txHash)
+			}
+			req, msg := srv.last()
+			if msg.Method != _SendRawTx || string(msg.Params) != `["`+rawTx+`"]` {
+				t.Fatalf("request %s %s", msg.Method, msg.Params)
+			}
+			if req.URL.Path != tt.wantPath {
+				t.Fatalf("path = %s, want %s", req.URL.Path, tt.wantPath)
+			}
+			if got := req.URL.Query(); len(got) != len(tt.wantQuery) || got.Encode() != tt.wantQuery.Encode() {
+				t.Fatalf("query = %s, want %s", got.Encode(), tt.wantQuery.Encode())
+			}
+			if sig := req.Header.Get("x-flashbots-signature"); sig != "" {
+				t.Fatalf("Protect request signed with %q", sig)
+			}
+		})
+	}
+}
+
+func TestTxStatusClient(t *testing.T) {
+	const txHash = "0x0000000000000000000000000000000000000000000000000000000000000abc"
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		wantStatus  string
+		check       func(t *testing.T, err error)
+	}{
+		{
+			name:        "included",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body: `{"status":"INCLUDED","hash":"` + txHash + `","maxBlockNumber":17000000,` +
+				`"transaction":{"from":"0x01","to":"0x02","gasLimit":"21000","maxFeePerGas":"100","maxPriorityFeePerGas":"2","nonce":"7","value":"0"},` +
+				`"fastMode":true,"seenInMempool":false}`,
+			wantStatus: common.TxStatusIncluded,
+		},
+		{
+			name:        "not found",
+			status:      http.StatusNotFound,
+			contentType: "text/plain",
+			body:        "not found",
+			check: func(t *testing.T, err error) {
+				var httpErr common.HTTPError
+				if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+					t.Fatalf("Status() error = %v, want a 404 HTTPError", err)
+				}
+			},
+		},
+		{
+			name:        "html page",
+			status:      http.StatusOK,
+			contentType: "text/html",
+			body:        "<html>maintenance</html>",
+			check: func(t *testing.T, err error) {
+				var ctErr *common.ContentTypeError
+				if !errors.As(err, &ctErr) {
+					t.Fatalf("Status() error = %v, want ContentTypeError", err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotMethod, gotPath string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotMethod, gotPath = r.Method, r.URL.Path
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			// the trailing slash is added when missing
+			res, err := NewTxStatusClient(srv.URL+"/tx").Status(context.Background(), txHash)
+			if gotMethod != http.MethodGet || gotPath != "/tx/"+txHash {
+				t.Fatalf("request %s %s", gotMethod, gotPath)
+			}
+			if tt.check != nil {
+				tt.check(t, err)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Status != tt.wantStatus || res.Hash != txHash || res.MaxBlockNumber != 17000000 ||
+				res.Transaction.Nonce != "7" || !res.FastMode {
+				t.Fatalf("Status() = %+v", res)
+			}
+		})
+	}
+}
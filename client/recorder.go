@@ -0,0 +1,266 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const signatureHeader = "x-flashbots-signature"
+
+// Exchange is one recorded JSON-RPC request and its response, stored as a JSONL line.
+type Exchange struct {
+	Time            time.Time       `json:"time"`
+	URL             string          `json:"url"`
+	Method          string          `json:"method"`
+	Params          json.RawMessage `json:"params,omitempty"`
+	RequestHeaders  http.Header     `json:"requestHeaders,omitempty"`
+	Status          int             `json:"status,omitempty"`
+	ResponseHeaders http.Header     `json:"responseHeaders,omitempty"`
+	Response        json.RawMessage `json:"response,omitempty"`     // body when it is valid JSON
+	ResponseText    string          `json:"responseText,omitempty"` // body otherwise
+	Latency         time.Duration   `json:"latency"`
+	Error           string          `json:"error,omitempty"` // transport or body read error, no response
+}
+
+func (e *Exchange) body() []byte {
+	if len(e.Response) > 0 {
+		return e.Response
+	}
+	return []byte(e.ResponseText)
+}
+
+// RecordingTransport forwards requests to the base transport and appends every exchange to w.
+// Response bodies are buffered up to DefaultMaxResponseSize, or the limit of the HttpClient
+// for transports created by RecordTo.
+type RecordingTransport struct {
+	base    io.Closer
+	rt      http.RoundTripper
+	maxBody int64
+	mu      sync.Mutex // protects w
+	w       io.Writer
+}
+
+func NewRecordingTransport(w io.Writer, base http.RoundTripper) *RecordingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RecordingTransport{rt: base, maxBody: DefaultMaxResponseSize, w: w}
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ex := Exchange{
+		Time:           time.Now().UTC(),
+		URL:            req.URL.String(),
+		RequestHeaders: req.Header.Clone(),
+	}
+	ex.RequestHeaders.Del(signatureHeader)
+	// read a copy so the request, which a RoundTripper must not modify, is sent untouched
+	if reqBody, err := copyRequestBody(req); err == nil {
+		ex.Method, ex.Params = rpcMethod(reqBody)
+	}
+
+	start := time.Now()
+	resp, err := t.rt.RoundTrip(req)
+	ex.Latency = time.Since(start)
+	if err != nil {
+		ex.Error = err.Error()
+		t.write(&ex)
+		return nil, err
+	}
+	respBody, err := io.ReadAll(newLimitedBody(resp.Body, t.maxBody))
+	resp.Body.Close()
+	ex.Latency = time.Since(start)
+	ex.Status = resp.StatusCode
+	ex.ResponseHeaders = resp.Header.Clone()
+	if err != nil {
+		ex.Error = err.Error()
+		t.write(&ex)
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if json.Valid(respBody) {
+		ex.Response = respBody
+	} else {
+		ex.ResponseText = string(respBody)
+	}
+	t.write(&ex)
+	return resp, nil
+}
+
+func (t *RecordingTransport) write(ex *Exchange) {
+	b, err := json.Marshal(ex)
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = t.w.Write(append(b, '\n'))
+}
+
+// Close closes the file opened by HttpClient.RecordTo.
+func (t *RecordingTransport) Close() error {
+	if t.base == nil {
+		return nil
+	}
+	return t.base.Close()
+}
+
+// ReplayTransport serves recorded responses instead of sending requests. Exchanges are
+// matched by JSON-RPC method and params and served in recording order.
+type ReplayTransport struct {
+	mu        sync.Mutex // protects exchanges
+	exchanges map[string][]*Exchange
+}
+
+func NewReplayTransport(r io.Reader) (*ReplayTransport, error) {
+	t := &ReplayTransport{exchanges: make(map[string][]*Exchange)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		ex := new(Exchange)
+		if err := json.Unmarshal(scanner.Bytes(), ex); err != nil {
+			return nil, fmt.Errorf("replay: line %d: %w", line, err)
+		}
+		key := replayKey(ex.Method, ex.Params)
+		t.exchanges[key] = append(t.exchanges[key], ex)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	method, params := rpcMethod(reqBody)
+	key := replayKey(method, params)
+
+	t.mu.Lock()
+	queue := t.exchanges[key]
+	if len(queue) == 0 {
+		t.mu.Unlock()
+		return nil, fmt.Errorf("replay: no recorded response for %s", method)
+	}
+	ex := queue[0]
+	// keep serving the last exchange once the queue is drained
+	if len(queue) > 1 {
+		t.exchanges[key] = queue[1:]
+	}
+	t.mu.Unlock()
+
+	if ex.Error != "" {
+		return nil, errors.New(ex.Error)
+	}
+	header := ex.ResponseHeaders.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	body := ex.body()
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", ex.Status, http.StatusText(ex.Status)),
+		StatusCode:    ex.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// SetTransport replaces the transport of the underlying http.Client.
+func (hc *HttpClient) SetTransport(rt http.RoundTripper) {
+	hc.client.Transport = rt
+}
+
+// RecordTo appends every exchange of hc to the JSONL file at path. Close the returned
+// transport to flush and close the file.
+func (hc *HttpClient) RecordTo(path string) (*RecordingTransport, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	t := NewRecordingTransport(f, hc.client.Transport)
+	t.base = f
+	t.maxBody = hc.responseLimit()
+	hc.SetTransport(t)
+	return t, nil
+}
+
+// ReplayFrom serves every request of hc from the JSONL file at path, without network access.
+func (hc *HttpClient) ReplayFrom(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	t, err := NewReplayTransport(f)
+	if err != nil {
+		return err
+	}
+	hc.SetTransport(t)
+	return nil
+}
+
+// copyRequestBody reads the body of req through GetBody, leaving req.Body unread.
+func copyRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("request body cannot be copied")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// readRequestBody consumes the body of a request that is not forwarded.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+func rpcMethod(body []byte) (string, json.RawMessage) {
+	var msg struct {
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return "", nil
+	}
+	return msg.Method, msg.Params
+}
+
+func replayKey(method string, params json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, params); err != nil {
+		buf.Reset()
+		buf.Write(params)
+	}
+	return method + "\x00" + strings.TrimSpace(buf.String())
+}
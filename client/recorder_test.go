@@ -0,0 +1,96 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRecordingTransport(t *testing.T) {
+	const payload = `{"jsonrpc":"2.0","id":1,"method":"eth_callBundle","params":[{"txs":["0x01"]}]}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != payload {
+			t.Errorf("server received %q", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/large" {
+			_, _ = w.Write([]byte(`{"result":"` + strings.Repeat("a", 64) + `"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"bundleHash":"0x01"}}`))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		path       string
+		maxBody    int64
+		wantErr    error
+		wantMethod string
+	}{
+		{"recorded", "/", DefaultMaxResponseSize, nil, "eth_callBundle"},
+		{"response too large", "/large", 32, ErrResponseTooLarge, "eth_callBundle"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			rt := NewRecordingTransport(&out, nil)
+			rt.maxBody = tt.maxBody
+			req, err := http.NewRequest(http.MethodPost, srv.URL+tt.path, strings.NewReader(payload))
+			if err != nil {
+				t.Fatal(err)
+			}
+			body := req.Body
+			resp, err := rt.RoundTrip(req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RoundTrip() error = %v, want %v", err, tt.wantErr)
+			}
+			if req.Body != body {
+				t.Fatal("RoundTrip() replaced the request body")
+			}
+			if err == nil {
+				if b, _ := io.ReadAll(resp.Body); !json.Valid(b) {
+					t.Fatalf("response body %q", b)
+				}
+			}
+
+			var ex Exchange
+			if err := json.Unmarshal(out.Bytes(), &ex); err != nil {
+				t.Fatalf("no exchange recorded: %v", err)
+			}
+			if ex.Method != tt.wantMethod || ex.Status != http.StatusOK {
+				t.Fatalf("recorded %+v", ex)
+			}
+			if (ex.Error != "") != (tt.wantErr != nil) {
+				t.Fatalf("recorded error %q, want %v", ex.Error, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecordingTransportWithoutGetBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	rt := NewRecordingTransport(&out, nil)
+	req, err := http.NewRequest(http.MethodPost, srv.URL, io.NopCloser(strings.NewReader(`{"method":"eth_sendBundle"}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = rt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	var ex Exchange
+	if err := json.Unmarshal(out.Bytes(), &ex); err != nil || ex.Method != "" || ex.Status != http.StatusOK {
+		t.Fatalf("recorded %+v, %v", ex, err)
+	}
+}
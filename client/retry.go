@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// RetryPolicy retries requests failing with a network error or a retryable HTTP status,
+// see common.HTTPError.Retryable. A Retry-After header lengthens the backoff up to MaxBackoff.
+// The zero value sends every request once.
+type RetryPolicy struct {
+	MaxAttempts    int           // total attempts including the first one
+	InitialBackoff time.Duration // wait before the second attempt, doubled after each retry
+	MaxBackoff     time.Duration // upper bound of the wait, unbounded when zero
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return d
+}
+
+// SetRetryPolicy configures retries of CallContext. Not safe to call concurrently with requests.
+func (hc *HttpClient) SetRetryPolicy(policy RetryPolicy) {
+	hc.retry = policy
+}
+
+// SetRateLimit caps outgoing requests to rps per second with the given burst. A zero rps removes the limit.
+// Not safe to call concurrently with requests.
+func (hc *HttpClient) SetRateLimit(rps float64, burst int) {
+	if rps <= 0 {
+		hc.limiter = nil
+		return
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	hc.limiter = rate.NewLimiter(rate.Limit(rps), burst)
+}
+
+// SetTimeout sets the timeout of a single HTTP request.
+func (hc *HttpClient) SetTimeout(timeout time.Duration) {
+	hc.client.Timeout = timeout
+}
+
+// SetHeader adds a header sent with every request.
+func (hc *HttpClient) SetHeader(key, value string) {
+	hc.mu.Lock()
+	hc.headers.Set(key, value)
+	hc.mu.Unlock()
+}
+
+// doWithRetry calls fn until it succeeds, fails with a non-retryable error or the policy is exhausted.
+func (hc *HttpClient) doWithRetry(ctx context.Context, fn func() error) error {
+	attempts := hc.retry.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	var err error
+	for attempt := 1; ; attempt++ {
+		if hc.limiter != nil {
+			if err = hc.limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+		if err = fn(); err == nil || attempt >= attempts || !isRetryable(ctx, err) {
+			return err
+		}
+		wait := hc.retry.backoff(attempt)
+		var httpErr common.HTTPError
+		if errors.As(err, &httpErr) && httpErr.RetryAfter() > wait {
+			wait = httpErr.RetryAfter()
+			if hc.retry.MaxBackoff > 0 && wait > hc.retry.MaxBackoff {
+				wait = hc.retry.MaxBackoff
+			}
+		}
+		hc.logger.Debug("retrying request", zap.Int("attempt", attempt), zap.Duration("backoff", wait), zap.Error(err))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var httpErr common.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Retryable()
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
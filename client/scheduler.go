@@ -0,0 +1,295 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"go.uber.org/zap"
+)
+
+// ErrSlotPassed is returned when scheduling a bundle for a slot that has already started.
+var ErrSlotPassed = errors.New("slot has already started")
+
+type SubmissionStatus int
+
+const (
+	SubmissionSent SubmissionStatus = iota
+	SubmissionFailed
+	// SubmissionStale means the slot started before the bundle could be sent.
+	SubmissionStale
+)
+
+func (s SubmissionStatus) String() string {
+	switch s {
+	case SubmissionSent:
+		return "sent"
+	case SubmissionFailed:
+		return "failed"
+	default:
+		return "stale"
+	}
+}
+
+// SubmissionResult is the outcome of one scheduled bundle.
+type SubmissionResult struct {
+	Bundle common.SendBundleArgs // with the block number resolved
+	// BlockEstimated is set when the block number was derived from the head, see Schedule.
+	BlockEstimated bool
+	Status         SubmissionStatus
+	Response       *common.SendBundleResponse
+	Err            error
+	SentAt         time.Time
+	Latency        time.Duration
+}
+
+// SlotOutcome reports every bundle scheduled for a slot once its submissions completed.
+type SlotOutcome struct {
+	Slot     uint64
+	Deadline time.Time // start of the slot
+	Results  []SubmissionResult
+}
+
+// Count returns the number of results with the given status.
+func (o SlotOutcome) Count(status SubmissionStatus) int {
+	n := 0
+	for _, r := range o.Results {
+		if r.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// SlotScheduler sends bundles through FlashbotsClient.SendBundle at a fixed offset before
+// the start of their target slot, when the proposer requests the block from builders.
+// Submissions still in flight when the slot starts are cancelled and reported stale.
+type SlotScheduler struct {
+	logger *zap.Logger
+	fbc    *FlashbotsClient
+	chain  ChainReader
+	clock  common.SlotClock
+	offset time.Duration
+
+	mu     sync.Mutex // protects queue
+	queue  map[uint64][]common.SendBundleArgs
+	wake   chan struct{}
+	events chan SlotOutcome
+}
+
+// NewSlotScheduler creates a scheduler sending offset before each slot starts. chain is
+// optional and only used to derive the block number of bundles scheduled without one.
+func NewSlotScheduler(fbc *FlashbotsClient, chain ChainReader, clock common.SlotClock, offset time.Duration) *SlotScheduler {
+	return &SlotScheduler{
+		logger: common.NewLogger(),
+		fbc:    fbc,
+		chain:  chain,
+		clock:  clock,
+		offset: offset,
+		queue:  make(map[uint64][]common.SendBundleArgs),
+		wake:   make(chan struct{}, 1),
+		events: make(chan SlotOutcome, 64),
+	}
+}
+
+// NewSlotSchedulerForNetwork uses the slot timing of the network profile.
+func NewSlotSchedulerForNetwork(fbc *FlashbotsClient, chain ChainReader, network common.Network, offset time.Duration) (*SlotScheduler, error) {
+	clock, err := network.SlotClock()
+	if err != nil {
+		return nil, err
+	}
+	return NewSlotScheduler(fbc, chain, clock, offset), nil
+}
+
+func (s *SlotScheduler) Outcomes() <-chan SlotOutcome {
+	return s.events
+}
+
+// SubmitTime returns when bundles for slot are sent.
+func (s *SlotScheduler) SubmitTime(slot uint64) time.Time {
+	return s.clock.SlotStart(slot).Add(-s.offset)
+}
+
+// NextSlot returns the first slot whose submit time has not passed yet.
+func (s *SlotScheduler) NextSlot() uint64 {
+	now := time.Now()
+	slot := s.clock.SlotAt(now) + 1
+	for !s.SubmitTime(slot).After(now) {
+		slot++
+	}
+	return slot
+}
+
+// Schedule queues bundle for slot. A slot whose submit time has passed but which has not
+// started yet is sent immediately.
+//
+// A bundle without block number targets the block of that slot, derived from the latest
+// head when it is sent. Slots that ended without extending the head count as missed, the
+// ones still to come are assumed to produce a block. The result is therefore an estimate,
+// reported by SubmissionResult.BlockEstimated; set BlockNumber when it must be exact.
+func (s *SlotScheduler) Schedule(slot uint64, bundle common.SendBundleArgs) error {
+	if !time.Now().Before(s.clock.SlotStart(slot)) {
+		return fmt.Errorf("slot %d: %w", slot, ErrSlotPassed)
+	}
+	if bundle.BlockNumber == "" && s.chain == nil {
+		return errors.New("bundle without block number needs a chain reader")
+	}
+	s.mu.Lock()
+	s.queue[slot] = append(s.queue[slot], bundle)
+	s.mu.Unlock()
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run sends the queued bundles slot by slot until ctx is cancelled.
+func (s *SlotScheduler) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		slot, ok := s.nextQueued()
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if ok {
+			timer = time.NewTimer(time.Until(s.SubmitTime(slot)))
+			fire = timer.C
+		}
+		fired := false
+		select {
+		case <-ctx.Done():
+		case <-s.wake:
+		case <-fire:
+			fired = true
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !fired {
+			continue
+		}
+
+		s.mu.Lock()
+		bundles := s.queue[slot]
+		delete(s.queue, slot)
+		s.mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outcome := s.submit(ctx, slot, bundles)
+			select {
+			case s.events <- outcome:
+			case <-ctx.Done():
+			}
+		}()
+	}
+}
+
+func (s *SlotScheduler) nextQueued() (uint64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slots := make([]uint64, 0, len(s.queue))
+	for slot := range s.queue {
+		slots = append(slots, slot)
+	}
+	if len(slots) == 0 {
+		return 0, false
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+	return slots[0], true
+}
+
+// submit sends the bundles of slot concurrently, cancelling them when the slot starts.
+func (s *SlotScheduler) submit(ctx context.Context, slot uint64, bundles []common.SendBundleArgs) SlotOutcome {
+	deadline := s.clock.SlotStart(slot)
+	outcome := SlotOutcome{
+		Slot:     slot,
+		Deadline: deadline,
+		Results:  make([]SubmissionResult, len(bundles)),
+	}
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	var blockNumber string
+	var blockErr error
+	for _, b := range bundles {
+		if b.BlockNumber == "" {
+			blockNumber, blockErr = s.blockNumber(ctx, slot)
+			break
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, bundle := range bundles {
+		res := &outcome.Results[i]
+		res.Bundle = bundle
+		if bundle.BlockNumber == "" {
+			if blockErr != nil {
+				res.Status, res.Err = statusOf(ctx, blockErr), blockErr
+				continue
+			}
+			res.Bundle.BlockNumber = blockNumber
+			res.BlockEstimated = true
+		}
+		if ctx.Err() != nil {
+			res.Status, res.Err = SubmissionStale, ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res.SentAt = time.Now()
+			res.Response, res.Err = s.fbc.SendBundle(ctx, []common.SendBundleArgs{res.Bundle})
+			res.Latency = time.Since(res.SentAt)
+			if res.Err != nil {
+				res.Status = statusOf(ctx, res.Err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	s.logger.Debug("slot submitted",
+		zap.Uint64("slot", slot),
+		zap.Int("sent", outcome.Count(SubmissionSent)),
+		zap.Int("failed", outcome.Count(SubmissionFailed)),
+		zap.Int("stale", outcome.Count(SubmissionStale)),
+	)
+	return outcome
+}
+
+// blockNumber estimates the block of slot from the latest head. Slots that ended after
+// the head are missed, the current and later slots are each expected to add a block.
+func (s *SlotScheduler) blockNumber(ctx context.Context, slot uint64) (string, error) {
+	head, err := s.chain.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get latest header: %w", err)
+	}
+	base := s.clock.SlotAt(time.Unix(int64(head.Time), 0))
+	if current := s.clock.CurrentSlot(); current > 0 && current-1 > base {
+		base = current - 1
+	}
+	if slot <= base {
+		return "", fmt.Errorf("slot %d: %w", slot, ErrSlotPassed)
+	}
+	number := new(big.Int).Add(head.Number, new(big.Int).SetUint64(slot-base))
+	return hexutil.EncodeBig(number), nil
+}
+
+// statusOf reports a failure caused by the slot deadline as stale.
+func statusOf(ctx context.Context, err error) SubmissionStatus {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(err, ErrSlotPassed) {
+		return SubmissionStale
+	}
+	return SubmissionFailed
+}
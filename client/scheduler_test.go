@@ -0,0 +1,232 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// fakeHeadChain always serves the same head.
+type fakeHeadChain struct {
+	head *types.Header
+	err  error
+}
+
+func (c *fakeHeadChain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return c.head, c.err
+}
+
+func (c *fakeHeadChain) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fakeHeadChain) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return nil, errors.New("not implemented")
+}
+
+// sendFunc answers eth_sendBundle calls and counts them.
+type sendFunc struct {
+	mu    sync.Mutex
+	calls int
+	fn    func(ctx context.Context) (*common.JSONRPCMessage, error)
+}
+
+func (s *sendFunc) CallContext(ctx context.Context, msg interface{}) (*common.JSONRPCMessage, error) {
+	s.mu.Lock()
+	s.calls++
+	s.mu.Unlock()
+	return s.fn(ctx)
+}
+
+func (s *sendFunc) Use(interceptors ...Interceptor) {}
+
+func (s *sendFunc) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func newTestScheduler(transport Transport, chain ChainReader, clock common.SlotClock, offset time.Duration) *SlotScheduler {
+	s := NewSlotScheduler(&FlashbotsClient{logger: zap.NewNop(), transport: transport}, chain, clock, offset)
+	s.logger = zap.NewNop()
+	return s
+}
+
+func TestSlotSchedulerSchedule(t *testing.T) {
+	clock := common.SlotClock{Genesis: time.Now().Add(-time.Hour)}
+	bundle := common.SendBundleArgs{Txs: []string{"0x01"}, BlockNumber: "0x10"}
+	current := clock.CurrentSlot()
+	tests := []struct {
+		name    string
+		chain   ChainReader
+		slot    uint64
+		bundle  common.SendBundleArgs
+		wantErr error
+	}{
+		{name: "next slot", slot: current + 1, bundle: bundle},
+		{name: "current slot", slot: current, bundle: bundle, wantErr: ErrSlotPassed},
+		{name: "past slot", slot: current - 1, bundle: bundle, wantErr: ErrSlotPassed},
+		{name: "derived block", chain: &fakeHeadChain{}, slot: current + 2, bundle: common.SendBundleArgs{Txs: []string{"0x01"}}},
+		{name: "derived block without chain", slot: current + 2, bundle: common.SendBundleArgs{Txs: []string{"0x01"}}, wantErr: errors.New("")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScheduler(&sendFunc{}, tt.chain, clock, time.Second)
+			err := s.Schedule(tt.slot, tt.bundle)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if slot, ok := s.nextQueued(); !ok || slot != tt.slot {
+					t.Fatalf("queued slot = %d, %v, want %d", slot, ok, tt.slot)
+				}
+				return
+			}
+			if err == nil || (tt.wantErr.Error() != "" && !errors.Is(err, tt.wantErr)) {
+				t.Fatalf("Schedule() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, ok := s.nextQueued(); ok {
+				t.Fatal("rejected bundle was queued")
+			}
+		})
+	}
+}
+
+func TestSlotSchedulerBlockNumber(t *testing.T) {
+	const slot = 100
+	// the current slot is slot-1, half way through
+	genesis := time.Now().Truncate(time.Second).Add(-(slot-1)*12*time.Second - 6*time.Second)
+	clock := common.SlotClock{Genesis: genesis}
+	headAt := func(headSlot uint64) *types.Header {
+		return &types.Header{Number: big.NewInt(1000), Time: uint64(clock.SlotStart(headSlot).Add(time.Second).Unix())}
+	}
+	tests := []struct {
+		name     string
+		chain    *fakeHeadChain
+		slot     uint64
+		want     uint64
+		wantErr  error
+		anyError bool
+	}{
+		{name: "head in the current slot", chain: &fakeHeadChain{head: headAt(slot - 1)}, slot: slot, want: 1001},
+		{name: "current slot still to come", chain: &fakeHeadChain{head: headAt(slot - 2)}, slot: slot, want: 1002},
+		{name: "missed slots", chain: &fakeHeadChain{head: headAt(slot - 5)}, slot: slot, want: 1002},
+		{name: "later slot", chain: &fakeHeadChain{head: headAt(slot - 1)}, slot: slot + 3, want: 1004},
+		{name: "head in the target slot", chain: &fakeHeadChain{head: headAt(slot)}, slot: slot, wantErr: ErrSlotPassed},
+		{name: "slot ended", chain: &fakeHeadChain{head: headAt(slot - 5)}, slot: slot - 2, wantErr: ErrSlotPassed},
+		{name: "header error", chain: &fakeHeadChain{err: errors.New("node down")}, slot: slot, anyError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScheduler(&sendFunc{}, tt.chain, clock, time.Second)
+			got, err := s.blockNumber(context.Background(), tt.slot)
+			if tt.wantErr != nil || tt.anyError {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("blockNumber() = %s, %v, want error %v", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != hexutil.EncodeUint64(tt.want) {
+				t.Fatalf("blockNumber() = %s, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlotSchedulerRun(t *testing.T) {
+	sent := func(ctx context.Context) (*common.JSONRPCMessage, error) {
+		return &common.JSONRPCMessage{Result: []byte(`{"bundleHash":"0x01"}`)}, nil
+	}
+	blocked := func(ctx context.Context) (*common.JSONRPCMessage, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	failed := func(ctx context.Context) (*common.JSONRPCMessage, error) {
+		return nil, errors.New("relay unavailable")
+	}
+	tests := []struct {
+		name       string
+		send       func(ctx context.Context) (*common.JSONRPCMessage, error)
+		wantStatus SubmissionStatus
+	}{
+		{"sent", sent, SubmissionSent},
+		{"stale", blocked, SubmissionStale},
+		{"failed", failed, SubmissionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := common.SlotClock{Genesis: time.Now(), SlotDuration: 200 * time.Millisecond}
+			transport := &sendFunc{fn: tt.send}
+			chain := &fakeHeadChain{head: &types.Header{Number: big.NewInt(1000)}}
+			s := newTestScheduler(transport, chain, clock, 50*time.Millisecond)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			first := s.NextSlot()
+			explicit := common.SendBundleArgs{Txs: []string{"0x01"}, BlockNumber: "0x10"}
+			derived := common.SendBundleArgs{Txs: []string{"0x02"}}
+			for _, b := range []common.SendBundleArgs{explicit, derived} {
+				if err := s.Schedule(first, b); err != nil {
+					t.Fatal(err)
+				}
+			}
+			done := make(chan error, 1)
+			go func() { done <- s.Run(ctx) }()
+			// scheduled while running, reported after the first slot
+			if err := s.Schedule(first+1, explicit); err != nil {
+				t.Fatal(err)
+			}
+
+			for i, slot := range []uint64{first, first + 1} {
+				var outcome SlotOutcome
+				select {
+				case outcome = <-s.Outcomes():
+				case <-ctx.Done():
+					t.Fatal("no outcome reported")
+				}
+				if outcome.Slot != slot || !outcome.Deadline.Equal(clock.SlotStart(slot)) {
+					t.Fatalf("outcome %d for slot %d, want %d", i, outcome.Slot, slot)
+				}
+				if want := 2 - i; len(outcome.Results) != want || outcome.Count(tt.wantStatus) != want {
+					t.Fatalf("slot %d results = %+v, want %d %s", slot, outcome.Results, want, tt.wantStatus)
+				}
+				for _, res := range outcome.Results {
+					if (res.Err == nil) != (tt.wantStatus == SubmissionSent) {
+						t.Fatalf("result error = %v for status %s", res.Err, res.Status)
+					}
+					if res.SentAt.Before(s.SubmitTime(slot)) || !res.SentAt.Before(outcome.Deadline) {
+						t.Fatalf("sent at %v, want between %v and %v", res.SentAt, s.SubmitTime(slot), outcome.Deadline)
+					}
+				}
+				if i == 0 {
+					if outcome.Results[0].BlockEstimated || outcome.Results[0].Bundle.BlockNumber != "0x10" {
+						t.Fatalf("explicit bundle = %+v", outcome.Results[0])
+					}
+					if !outcome.Results[1].BlockEstimated || outcome.Results[1].Bundle.BlockNumber == "" {
+						t.Fatalf("derived bundle = %+v", outcome.Results[1])
+					}
+				}
+			}
+			if n := transport.count(); n != 3 {
+				t.Fatalf("sent %d bundles, want 3", n)
+			}
+
+			cancel()
+			if err := <-done; !errors.Is(err, context.Canceled) {
+				t.Fatalf("Run() = %v, want context.Canceled", err)
+			}
+		})
+	}
+}
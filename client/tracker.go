@@ -0,0 +1,321 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// ChainReader is the subset of ethclient.Client needed to follow the chain.
+type ChainReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+}
+
+type InclusionStatus int
+
+const (
+	NotIncluded InclusionStatus = iota
+	Included
+	PartiallyIncluded
+)
+
+func (s InclusionStatus) String() string {
+	switch s {
+	case Included:
+		return "included"
+	case PartiallyIncluded:
+		return "partially_included"
+	default:
+		return "not_included"
+	}
+}
+
+func (s InclusionStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// TrackedBundle identifies a submitted bundle and the block it targets.
+type TrackedBundle struct {
+	BundleHash  string
+	TxHashes    []gethcommon.Hash
+	BlockNumber uint64
+}
+
+// NewTrackedBundle derives the tx hashes and target block from the arguments passed to SendBundle.
+func NewTrackedBundle(arg common.SendBundleArgs, res *common.SendBundleResponse) (TrackedBundle, error) {
+	if len(arg.Txs) == 0 {
+		return TrackedBundle{}, errNoTxs
+	}
+	blockNumber, err := hexutil.DecodeUint64(arg.BlockNumber)
+	if err != nil {
+		return TrackedBundle{}, fmt.Errorf("invalid block number %q: %w", arg.BlockNumber, err)
+	}
+	txHashes := make([]gethcommon.Hash, 0, len(arg.Txs))
+	for i, rawTx := range arg.Txs {
+		b, err := hexutil.Decode(rawTx)
+		if err != nil {
+			return TrackedBundle{}, fmt.Errorf("invalid tx at index %d: %w", i, err)
+		}
+		tx := new(types.Transaction)
+		if err = tx.UnmarshalBinary(b); err != nil {
+			return TrackedBundle{}, fmt.Errorf("invalid tx at index %d: %w", i, err)
+		}
+		txHashes = append(txHashes, tx.Hash())
+	}
+	tb := TrackedBundle{
+		TxHashes:    txHashes,
+		BlockNumber: blockNumber,
+	}
+	if res != nil {
+		tb.BundleHash = res.BundleHash
+	}
+	return tb, nil
+}
+
+// InclusionEvent reports the outcome of a tracked bundle once its target block is known.
+type InclusionEvent struct {
+	Bundle    TrackedBundle
+	Status    InclusionStatus
+	BlockHash gethcommon.Hash
+	// Position is the index in the block of the first bundle tx found, or -1.
+	Position int
+	// Found lists the bundle txs present in the block.
+	Found []gethcommon.Hash
+	// Stats and StatsV2 are the relay's view of the bundle, nil when unavailable.
+	Stats   *common.BundleStatsResponse
+	StatsV2 *common.BundleStatsResponseV2
+	// Err is set when the inclusion could not be determined, Status is then NotIncluded.
+	Err error
+}
+
+// maxCheckAttempts bounds the heads on which a failing inclusion check is retried.
+const maxCheckAttempts = 5
+
+// errNoTxs is permanent and never retried.
+var errNoTxs = errors.New("bundle has no transactions")
+
+type trackedEntry struct {
+	bundle   TrackedBundle
+	attempts int
+}
+
+// Tracker watches new heads and reports whether tracked bundles landed in their target block.
+type Tracker struct {
+	logger       *zap.Logger
+	fbc          *FlashbotsClient
+	chain        ChainReader
+	pollInterval time.Duration
+
+	mu      sync.Mutex // protects bundles
+	bundles []trackedEntry
+	events  chan InclusionEvent
+}
+
+// NewTracker creates a tracker. fbc is optional, it is used to fetch bundle stats and to
+// record the inclusion status with the audit sink of fbc.
+func NewTracker(fbc *FlashbotsClient, chain ChainReader) *Tracker {
+	return &Tracker{
+		logger:       common.NewLogger(),
+		fbc:          fbc,
+		chain:        chain,
+		pollInterval: time.Second * 2,
+		events:       make(chan InclusionEvent, 64),
+	}
+}
+
+func (t *Tracker) Add(b TrackedBundle) {
+	t.add(trackedEntry{bundle: b})
+}
+
+func (t *Tracker) add(e trackedEntry) {
+	t.mu.Lock()
+	t.bundles = append(t.bundles, e)
+	t.mu.Unlock()
+}
+
+func (t *Tracker) Events() <-chan InclusionEvent {
+	return t.events
+}
+
+// Run follows the chain until ctx is cancelled, emitting one event per tracked bundle
+// once its target block has been mined. A bundle whose check keeps failing is reported
+// with InclusionEvent.Err after maxCheckAttempts heads.
+func (t *Tracker) Run(ctx context.Context) error {
+	return followHeads(ctx, t.logger, t.chain, t.pollInterval, func(head *types.Header) error {
+		return t.process(ctx, head.Number.Uint64())
+	})
+}
+
+// followHeads calls fn for every new head, falling back to polling when the node
+// does not support subscriptions.
+func followHeads(ctx context.Context, logger *zap.Logger, chain ChainReader, pollInterval time.Duration, fn func(head *types.Header) error) error {
+	heads := make(chan *types.Header, 16)
+	sub, err := chain.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		logger.Warn("new head subscription unavailable, polling instead", zap.Error(err))
+		return pollHeads(ctx, logger, chain, pollInterval, fn)
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err = <-sub.Err():
+			return err
+		case head := <-heads:
+			if err = fn(head); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func pollHeads(ctx context.Context, logger *zap.Logger, chain ChainReader, pollInterval time.Duration, fn func(head *types.Header) error) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	var last uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			head, err := chain.HeaderByNumber(ctx, nil)
+			if err != nil {
+				logger.Warn("failed to get latest header", zap.Error(err))
+				continue
+			}
+			if n := head.Number.Uint64(); n > last {
+				last = n
+				if err = fn(head); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (t *Tracker) process(ctx context.Context, head uint64) error {
+	t.mu.Lock()
+	var due, pending []trackedEntry
+	for _, e := range t.bundles {
+		if e.bundle.BlockNumber <= head {
+			due = append(due, e)
+		} else {
+			pending = append(pending, e)
+		}
+	}
+	t.bundles = pending
+	t.mu.Unlock()
+
+	for _, e := range due {
+		ev, err := t.Check(ctx, e.bundle)
+		if err != nil {
+			e.attempts++
+			if !errors.Is(err, errNoTxs) && e.attempts < maxCheckAttempts && ctx.Err() == nil {
+				t.logger.Warn("failed to check bundle inclusion, retrying on the next head",
+					zap.String("bundleHash", e.bundle.BundleHash), zap.Int("attempt", e.attempts), zap.Error(err))
+				t.add(e)
+				continue
+			}
+			t.logger.Error("giving up on bundle inclusion check", zap.String("bundleHash", e.bundle.BundleHash), zap.Error(err))
+			ev = &InclusionEvent{Bundle: e.bundle, Status: NotIncluded, Position: -1, Err: err}
+		}
+		if t.fbc != nil && ev.Err == nil {
+			t.fbc.auditInclusion(ev)
+		}
+		select {
+		case t.events <- *ev:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Check inspects the target block of b, which must already be mined.
+func (t *Tracker) Check(ctx context.Context, b TrackedBundle) (*InclusionEvent, error) {
+	if len(b.TxHashes) == 0 {
+		return nil, errNoTxs
+	}
+	block, err := t.chain.BlockByNumber(ctx, new(big.Int).SetUint64(b.BlockNumber))
+	if err != nil {
+		return nil, err
+	}
+	ev := matchBundle(b, block)
+	t.attachStats(ctx, ev)
+	return ev, nil
+}
+
+// matchBundle reports Included only when every bundle tx appears in order and contiguously.
+func matchBundle(b TrackedBundle, block *types.Block) *InclusionEvent {
+	ev := &InclusionEvent{
+		Bundle:    b,
+		Status:    NotIncluded,
+		BlockHash: block.Hash(),
+		Position:  -1,
+	}
+	index := make(map[gethcommon.Hash]int, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		index[tx.Hash()] = i
+	}
+	contiguous := true
+	for i, h := range b.TxHashes {
+		pos, ok := index[h]
+		if !ok {
+			contiguous = false
+			continue
+		}
+		if ev.Position == -1 || pos < ev.Position {
+			ev.Position = pos
+		}
+		if i > 0 {
+			if prev, ok := index[b.TxHashes[i-1]]; !ok || prev != pos-1 {
+				contiguous = false
+			}
+		}
+		ev.Found = append(ev.Found, h)
+	}
+	switch {
+	case len(ev.Found) == 0:
+		ev.Status = NotIncluded
+	case len(ev.Found) == len(b.TxHashes) && contiguous:
+		ev.Status = Included
+	default:
+		ev.Status = PartiallyIncluded
+	}
+	return ev
+}
+
+func (t *Tracker) attachStats(ctx context.Context, ev *InclusionEvent) {
+	if t.fbc == nil || ev.Bundle.BundleHash == "" {
+		return
+	}
+	arg := []common.BundleStatsArgs{{
+		BundleHash:  ev.Bundle.BundleHash,
+		BlockNumber: hexutil.EncodeUint64(ev.Bundle.BlockNumber),
+	}}
+	stats, err := t.fbc.BundleStats(ctx, arg)
+	if err != nil {
+		t.logger.Debug("bundle stats unavailable", zap.String("bundleHash", ev.Bundle.BundleHash), zap.Error(err))
+	}
+	ev.Stats = stats
+	statsV2, err := t.fbc.BundleStatsV2(ctx, arg)
+	if err != nil {
+		t.logger.Debug("bundle stats v2 unavailable", zap.String("bundleHash", ev.Bundle.BundleHash), zap.Error(err))
+	}
+	ev.StatsV2 = statsV2
+}
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/ethereum/go-ethereum"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// fakeChain serves a growing head and the blocks registered in blocks. Blocks missing
+// from the map fail with errBlockUnavailable.
+type fakeChain struct {
+	mu     sync.Mutex
+	head   uint64
+	blocks map[uint64]*types.Block
+}
+
+var errBlockUnavailable = errors.New("block unavailable")
+
+func (c *fakeChain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.head++
+	return &types.Header{Number: new(big.Int).SetUint64(c.head)}, nil
+}
+
+func (c *fakeChain) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if b, ok := c.blocks[number.Uint64()]; ok {
+		return b, nil
+	}
+	return nil, errBlockUnavailable
+}
+
+func (c *fakeChain) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return nil, errors.New("subscriptions not supported")
+}
+
+func signedTxs(t *testing.T, n int) []*types.Transaction {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	to := gethcommon.HexToAddress("0x01")
+	txs := make([]*types.Transaction, n)
+	for i := range txs {
+		txs[i] = types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   big.NewInt(1),
+			Nonce:     uint64(i),
+			To:        &to,
+			Gas:       21000,
+			GasFeeCap: big.NewInt(1),
+			GasTipCap: big.NewInt(1),
+		})
+	}
+	return txs
+}
+
+func rawTxs(t *testing.T, txs []*types.Transaction) []string {
+	t.Helper()
+	raw := make([]string, len(txs))
+	for i, tx := range txs {
+		b, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw[i] = hexutil.Encode(b)
+	}
+	return raw
+}
+
+func TestNewTrackedBundle(t *testing.T) {
+	txs := signedTxs(t, 2)
+	tb, err := NewTrackedBundle(common.SendBundleArgs{Txs: rawTxs(t, txs), BlockNumber: "0x3"}, &common.SendBundleResponse{BundleHash: "0xab"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tb.BlockNumber != 3 || tb.BundleHash != "0xab" || len(tb.TxHashes) != 2 || tb.TxHashes[1] != txs[1].Hash() {
+		t.Fatalf("unexpected tracked bundle %+v", tb)
+	}
+	if _, err = NewTrackedBundle(common.SendBundleArgs{BlockNumber: "0x3"}, nil); !errors.Is(err, errNoTxs) {
+		t.Fatalf("empty bundle: got %v, want %v", err, errNoTxs)
+	}
+	if _, err = NewTrackedBundle(common.SendBundleArgs{Txs: rawTxs(t, txs), BlockNumber: "3"}, nil); err == nil {
+		t.Fatal("invalid block number accepted")
+	}
+}
+
+func TestTrackerLifecycle(t *testing.T) {
+	txs := signedTxs(t, 3)
+	header := &types.Header{Number: big.NewInt(2)}
+	block := types.NewBlockWithHeader(header).WithBody(txs[:2], nil)
+	chain := &fakeChain{blocks: map[uint64]*types.Block{2: block}}
+
+	tracker := NewTracker(nil, chain)
+	tracker.pollInterval = time.Millisecond
+	included := TrackedBundle{TxHashes: []gethcommon.Hash{txs[0].Hash(), txs[1].Hash()}, BlockNumber: 2}
+	partial := TrackedBundle{TxHashes: []gethcommon.Hash{txs[1].Hash(), txs[2].Hash()}, BlockNumber: 2}
+	unavailable := TrackedBundle{TxHashes: []gethcommon.Hash{txs[0].Hash()}, BlockNumber: 3}
+	empty := TrackedBundle{BlockNumber: 1}
+	for _, b := range []TrackedBundle{included, partial, unavailable, empty} {
+		tracker.Add(b)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- tracker.Run(ctx) }()
+
+	got := make(map[uint64][]InclusionEvent)
+	for i := 0; i < 4; i++ {
+		select {
+		case ev := <-tracker.Events():
+			got[ev.Bundle.BlockNumber] = append(got[ev.Bundle.BlockNumber], ev)
+		case <-ctx.Done():
+			t.Fatalf("got %d events, want 4", i)
+		}
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run returned %v", err)
+	}
+
+	if ev := got[1]; len(ev) != 1 || !errors.Is(ev[0].Err, errNoTxs) {
+		t.Fatalf("empty bundle: got %+v", ev)
+	}
+	if ev := got[3]; len(ev) != 1 || !errors.Is(ev[0].Err, errBlockUnavailable) || ev[0].Status != NotIncluded {
+		t.Fatalf("unavailable block: got %+v", ev)
+	}
+	statuses := map[InclusionStatus]InclusionEvent{}
+	for _, ev := range got[2] {
+		if ev.Err != nil {
+			t.Fatalf("unexpected error %v", ev.Err)
+		}
+		statuses[ev.Status] = ev
+	}
+	if ev, ok := statuses[Included]; !ok || ev.Position != 0 || ev.BlockHash != block.Hash() {
+		t.Fatalf("included bundle: got %+v", statuses)
+	}
+	if ev, ok := statuses[PartiallyIncluded]; !ok || ev.Position != 1 || len(ev.Found) != 1 {
+		t.Fatalf("partial bundle: got %+v", statuses)
+	}
+}
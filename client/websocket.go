@@ -0,0 +1,295 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPingInterval = 15 * time.Second
+	defaultPongTimeout  = 10 * time.Second
+	wsWriteTimeout      = 5 * time.Second
+	wsDialTimeout       = 5 * time.Second
+)
+
+var (
+	ErrClientClosed     = errors.New("websocket client closed")
+	ErrConnectionClosed = errors.New("websocket connection closed")
+)
+
+// WebSocketClient sends JSON-RPC requests over a persistent WebSocket connection,
+// multiplexing concurrent calls by request ID. A dropped connection fails the calls in
+// flight and is dialed again by the next call. Requests are not signed, pass any
+// authentication header at dial time.
+type WebSocketClient struct {
+	logger       *zap.Logger
+	url          string
+	header       http.Header
+	dialer       *websocket.Dialer
+	pingInterval time.Duration
+	pongTimeout  time.Duration
+	interceptors []Interceptor
+	nextID       uint64
+
+	dialMu  sync.Mutex // serializes dials
+	mu      sync.Mutex // protects conn, pending and closed
+	conn    *wsConn
+	pending map[string]chan wsResult
+	closed  bool
+}
+
+type wsConn struct {
+	*websocket.Conn
+	writeMu sync.Mutex // gorilla allows one concurrent writer
+	done    chan struct{}
+}
+
+type wsResult struct {
+	msg *common.JSONRPCMessage
+	err error
+}
+
+// DialWebSocketClient connects to rawURL (ws:// or wss://) sending header with the handshake.
+func DialWebSocketClient(ctx context.Context, rawURL string, header http.Header) (*WebSocketClient, error) {
+	wc := &WebSocketClient{
+		logger: common.NewLogger(),
+		url:    rawURL,
+		header: header,
+		dialer: &websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: wsDialTimeout,
+		},
+		pingInterval: defaultPingInterval,
+		pongTimeout:  defaultPongTimeout,
+		pending:      make(map[string]chan wsResult),
+	}
+	if _, err := wc.connect(ctx); err != nil {
+		return nil, err
+	}
+	return wc, nil
+}
+
+// SetPingInterval sets how often the connection is pinged and how long to wait for a pong
+// before it is considered dead. Applies to connections dialed afterwards.
+func (wc *WebSocketClient) SetPingInterval(interval, pongTimeout time.Duration) {
+	wc.mu.Lock()
+	wc.pingInterval, wc.pongTimeout = interval, pongTimeout
+	wc.mu.Unlock()
+}
+
+// Use appends interceptors to the chain. Not safe to call concurrently with requests.
+func (wc *WebSocketClient) Use(interceptors ...Interceptor) {
+	wc.interceptors = append(wc.interceptors, interceptors...)
+}
+
+func (wc *WebSocketClient) CallContext(ctx context.Context, msg interface{}) (*common.JSONRPCMessage, error) {
+	req, err := toMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	return chain(wc.interceptors, wc.invoke)(ctx, req)
+}
+
+func (wc *WebSocketClient) invoke(ctx context.Context, req *common.JSONRPCMessage) (*common.JSONRPCMessage, error) {
+	conn, err := wc.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the caller's ID is replaced by a unique one and restored in the response
+	id := strconv.FormatUint(atomic.AddUint64(&wc.nextID, 1), 10)
+	out := *req
+	out.ID = json.RawMessage(id)
+	payload, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan wsResult, 1)
+	wc.mu.Lock()
+	wc.pending[id] = ch
+	wc.mu.Unlock()
+	defer func() {
+		wc.mu.Lock()
+		delete(wc.pending, id)
+		wc.mu.Unlock()
+	}()
+
+	if err = conn.write(websocket.TextMessage, payload); err != nil {
+		wc.drop(conn, err)
+		return nil, err
+	}
+
+	select {
+	case res := <-ch:
+		if res.err != nil {
+			return nil, res.err
+		}
+		res.msg.ID = req.ID
+		return res.msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// connect returns the live connection, dialing a new one if needed.
+func (wc *WebSocketClient) connect(ctx context.Context) (*wsConn, error) {
+	wc.mu.Lock()
+	conn, closed := wc.conn, wc.closed
+	wc.mu.Unlock()
+	if closed {
+		return nil, ErrClientClosed
+	}
+	if conn != nil {
+		return conn, nil
+	}
+
+	wc.dialMu.Lock()
+	defer wc.dialMu.Unlock()
+	wc.mu.Lock()
+	conn, closed = wc.conn, wc.closed
+	pingInterval, pongTimeout := wc.pingInterval, wc.pongTimeout
+	wc.mu.Unlock()
+	if closed {
+		return nil, ErrClientClosed
+	}
+	if conn != nil {
+		return conn, nil
+	}
+
+	c, resp, err := wc.dialer.DialContext(ctx, wc.url, wc.header)
+	if err != nil {
+		if resp != nil {
+			httpErr := newHTTPError(resp, "")
+			resp.Body.Close()
+			return nil, httpErr
+		}
+		return nil, err
+	}
+	c.SetReadLimit(DefaultMaxResponseSize)
+	conn = &wsConn{Conn: c, done: make(chan struct{})}
+
+	wc.mu.Lock()
+	if wc.closed {
+		wc.mu.Unlock()
+		c.Close()
+		return nil, ErrClientClosed
+	}
+	wc.conn = conn
+	wc.mu.Unlock()
+
+	wc.logger.Debug("websocket connected", zap.String("url", wc.url))
+	go wc.readLoop(conn, pingInterval+pongTimeout)
+	go wc.pingLoop(conn, pingInterval)
+	return conn, nil
+}
+
+func (wc *WebSocketClient) readLoop(conn *wsConn, readTimeout time.Duration) {
+	_ = conn.SetReadDeadline(time.Now().Add(readTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(readTimeout))
+	})
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			wc.drop(conn, err)
+			return
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(readTimeout))
+
+		var msg *common.JSONRPCMessage
+		if err = json.Unmarshal(data, &msg); err != nil || msg == nil {
+			wc.logger.Warn("dropping invalid websocket message", zap.Error(err))
+			continue
+		}
+		// notifications carry no ID
+		if len(msg.ID) == 0 {
+			continue
+		}
+		wc.mu.Lock()
+		ch, ok := wc.pending[string(msg.ID)]
+		wc.mu.Unlock()
+		if !ok {
+			continue
+		}
+		// a duplicate response must not block the read loop, the first one wins
+		select {
+		case ch <- wsResult{msg: msg}:
+		default:
+		}
+	}
+}
+
+func (wc *WebSocketClient) pingLoop(conn *wsConn, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-conn.done:
+			return
+		case <-ticker.C:
+			if err := conn.write(websocket.PingMessage, nil); err != nil {
+				wc.drop(conn, err)
+				return
+			}
+		}
+	}
+}
+
+// drop closes conn and fails the calls waiting on it, once per connection.
+func (wc *WebSocketClient) drop(conn *wsConn, cause error) {
+	wc.mu.Lock()
+	if wc.conn != conn {
+		wc.mu.Unlock()
+		return
+	}
+	wc.conn = nil
+	pending := wc.pending
+	wc.pending = make(map[string]chan wsResult)
+	closed := wc.closed
+	wc.mu.Unlock()
+
+	close(conn.done)
+	conn.Close()
+	if !closed {
+		wc.logger.Warn("websocket connection lost", zap.String("url", wc.url), zap.Error(cause))
+	}
+	for _, ch := range pending {
+		// a call already answered keeps its response
+		select {
+		case ch <- wsResult{err: ErrConnectionClosed}:
+		default:
+		}
+	}
+}
+
+// Close closes the connection and fails the calls in flight.
+func (wc *WebSocketClient) Close() error {
+	wc.mu.Lock()
+	wc.closed = true
+	conn := wc.conn
+	wc.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	_ = conn.write(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	wc.drop(conn, ErrClientClosed)
+	return nil
+}
+
+func (c *wsConn) write(messageType int, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = c.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return c.WriteMessage(messageType, data)
+}
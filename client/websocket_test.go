@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/gorilla/websocket"
+)
+
+// strayID is answered by the stray method in addition to the request.
+const strayID = "1000000"
+
+// newFakeWSServer answers by method: echo once, dup twice, slow after 100ms, and stray
+// twice to strayID before answering. disconnect closes the connection.
+func newFakeWSServer(t *testing.T) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		defer c.Close()
+		var mu sync.Mutex
+		reply := func(req *common.JSONRPCMessage) {
+			mu.Lock()
+			defer mu.Unlock()
+			_ = c.WriteJSON(common.JSONRPCMessage{Version: common.JSONRPCVersion, ID: req.ID, Result: json.RawMessage(`"` + req.Method + `"`)})
+		}
+		for {
+			req := new(common.JSONRPCMessage)
+			if err := c.ReadJSON(req); err != nil {
+				return
+			}
+			switch req.Method {
+			case "echo":
+				reply(req)
+			case "dup":
+				reply(req)
+				reply(req)
+			case "stray":
+				reply(&common.JSONRPCMessage{ID: json.RawMessage(strayID), Method: req.Method})
+				reply(&common.JSONRPCMessage{ID: json.RawMessage(strayID), Method: req.Method})
+				reply(req)
+			case "slow":
+				time.AfterFunc(100*time.Millisecond, func() { reply(req) })
+			case "disconnect":
+				return
+			}
+		}
+	}))
+}
+
+func TestWebSocketClientLifecycle(t *testing.T) {
+	srv := newFakeWSServer(t)
+	defer srv.Close()
+	wc, err := DialWebSocketClient(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wc.Close()
+
+	tests := []struct {
+		name    string
+		method  string
+		timeout time.Duration
+		wantErr error
+	}{
+		{"call", "echo", time.Second, nil},
+		{"timeout", "slow", 20 * time.Millisecond, context.DeadlineExceeded},
+		{"late response ignored", "echo", time.Second, nil},
+		{"duplicate id", "dup", time.Second, nil},
+		{"after duplicate", "echo", time.Second, nil},
+		{"disconnect", "disconnect", time.Second, ErrConnectionClosed},
+		{"redial", "echo", time.Second, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			res, err := CallRaw(ctx, wc, tt.method, []interface{}{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CallRaw() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && string(res) != `"`+tt.method+`"` {
+				t.Fatalf("CallRaw() = %s, want %q", res, tt.method)
+			}
+		})
+	}
+	// let the late response of the timed out call arrive
+	time.Sleep(150 * time.Millisecond)
+	if _, err := CallRaw(context.Background(), wc, "echo", []interface{}{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := wc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CallRaw(context.Background(), wc, "echo", []interface{}{}); !errors.Is(err, ErrClientClosed) {
+		t.Fatalf("CallRaw() after Close error = %v, want ErrClientClosed", err)
+	}
+}
+
+func TestWebSocketClientConcurrent(t *testing.T) {
+	srv := newFakeWSServer(t)
+	defer srv.Close()
+	wc, err := DialWebSocketClient(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wc.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(method string) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				res, err := CallRaw(context.Background(), wc, method, []interface{}{})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if string(res) != `"`+method+`"` {
+					t.Errorf("response %s to %s", res, method)
+				}
+			}
+		}([]string{"echo", "dup"}[i%2])
+	}
+	wg.Wait()
+}
+
+// TestWebSocketClientUnreadResponses checks that responses nobody reads block neither the
+// read loop nor the teardown of a connection.
+func TestWebSocketClientUnreadResponses(t *testing.T) {
+	srv := newFakeWSServer(t)
+	defer srv.Close()
+	wc, err := DialWebSocketClient(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wc.Close()
+
+	// a call that stopped reading after its first response
+	wc.mu.Lock()
+	wc.pending[strayID] = make(chan wsResult, 1)
+	wc.mu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := CallRaw(ctx, wc, "stray", []interface{}{}); err != nil {
+		t.Fatalf("read loop blocked: %v", err)
+	}
+
+	wc.mu.Lock()
+	conn := wc.conn
+	wc.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		wc.drop(conn, errors.New("test"))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("drop blocked on an answered call")
+	}
+}
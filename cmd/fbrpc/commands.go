@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/util"
+)
+
+func newFlagSet(name string, env *cmdEnv) *flag.FlagSet {
+	fs := flag.NewFlagSet("fbrpc "+name, flag.ContinueOnError)
+	env.register(fs)
+	return fs
+}
+
+func optionalUint64(v uint64) *uint64 {
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
+
+func runCallBundle(ctx context.Context, env *cmdEnv, args []string) error {
+	fs := newFlagSet("call-bundle", env)
+	var txs stringList
+	fs.Var(&txs, "tx", "raw signed tx, repeatable")
+	txFile := fs.String("tx-file", "", "file with raw txs, - for stdin")
+	block := fs.String("block", "+1", "target block: hex, decimal or +N from the current head")
+	stateBlock := fs.String("state-block", "latest", "block number or tag to simulate on")
+	timestamp := fs.Uint64("timestamp", 0, "optional simulation timestamp in unix seconds")
+	decode := fs.Bool("decode", false, "print the decoded txs joined with their simulation results")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, env.timeout)
+	defer cancel()
+	fbc, err := env.client()
+	if err != nil {
+		return err
+	}
+	rawTxs, err := readTxs(env, txs, *txFile)
+	if err != nil {
+		return err
+	}
+	blockNum, err := resolveBlock(ctx, env, *block)
+	if err != nil {
+		return err
+	}
+	res, err := fbc.CallBundle(ctx, []common.CallBundleArgs{{
+		Txs:              rawTxs,
+		BlockNumber:      blockNum,
+		StateBlockNumber: *stateBlock,
+		Timestamp:        optionalUint64(*timestamp),
+	}})
+	if err != nil {
+		return err
+	}
+	if *decode {
+		decoded, err := util.InspectBundle(rawTxs, res)
+		if err != nil {
+			return err
+		}
+		return printDecodedTxs(env, decoded)
+	}
+	return printResult(env, res)
+}
+
+func runDecodeTxs(ctx context.Context, env *cmdEnv, args []string) error {
+	fs := newFlagSet("decode-txs", env)
+	var txs stringList
+	fs.Var(&txs, "tx", "raw signed tx, repeatable")
+	txFile := fs.String("tx-file", "", "file with raw txs, - for stdin")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	rawTxs, err := readTxs(env, txs, *txFile)
+	if err != nil {
+		return err
+	}
+	decoded, err := util.DecodeTxs(rawTxs)
+	if err != nil {
+		return err
+	}
+	return printDecodedTxs(env, decoded)
+}
+
+func runSendBundle(ctx context.Context, env *cmdEnv, args []string) error {
+	fs := newFlagSet("send-bundle", env)
+	var txs, reverting stringList
+	fs.Var(&txs, "tx", "raw signed tx, repeatable")
+	fs.Var(&reverting, "reverting-tx", "hash of a tx allowed to revert, repeatable")
+	txFile := fs.String("tx-file", "", "file with raw txs, - for stdin")
+	block := fs.String("block", "+1", "target block: hex, decimal or +N from the current head")
+	minTimestamp := fs.Uint64("min-timestamp", 0, "optional minimum timestamp in unix seconds")
+	maxTimestamp := fs.Uint64("max-timestamp", 0, "optional maximum timestamp in unix seconds")
+	replacementUuid := fs.String("replacement-uuid", "", "optional UUIDv4 to cancel or replace the bundle later")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, env.timeout)
+	defer cancel()
+	fbc, err := env.client()
+	if err != nil {
+		return err
+	}
+	rawTxs, err := readTxs(env, txs, *txFile)
+	if err != nil {
+		return err
+	}
+	blockNum, err := resolveBlock(ctx, env, *block)
+	if err != nil {
+		return err
+	}
+	res, err := fbc.SendBundle(ctx, []common.SendBundleArgs{{
+		Txs:               rawTxs,
+		BlockNumber:       blockNum,
+		MinTimestamp:      optionalUint64(*minTimestamp),
+		MaxTimestamp:      optionalUint64(*maxTimestamp),
+		RevertingTxHashes: reverting,
+		ReplacementUuid:   *replacementUuid,
+	}})
+	if err != nil {
+		return err
+	}
+	return printResult(env, res)
+}
+
+func runCancelBundle(ctx context.Context, env *cmdEnv, args []string) error {
+	fs := newFlagSet("cancel-bundle", env)
+	replacementUuid := fs.String("replacement-uuid", "", "UUIDv4 the bundle was sent with")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *replacementUuid == "" {
+		return errors.New("missing --replacement-uuid")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, env.timeout)
+	defer cancel()
+	fbc, err := env.client()
+	if err != nil {
+		return err
+	}
+	err = fbc.CancelBundle(ctx, []common.CancelBundleArgs{{
+		ReplacementUuid: *replacementUuid,
+	}})
+	if err != nil {
+		return err
+	}
+	return printResult(env, map[string]string{"replacementUuid": *replacementUuid, "status": "cancelled"})
+}
+
+func runSendPrivateTx(ctx context.Context, env *cmdEnv, args []string) error {
+	fs := newFlagSet("send-private-tx", env)
+	var txs stringList
+	fs.Var(&txs, "tx", "raw signed tx")
+	txFile := fs.String("tx-file", "", "file with the raw tx, - for stdin")
+	maxBlock := fs.String("max-block", "", "optional highest block for inclusion: hex, decimal or +N")
+	fast := fs.Bool("fast", false, "send to all builders")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, env.timeout)
+	defer cancel()
+	fbc, err := env.client()
+	if err != nil {
+		return err
+	}
+	rawTxs, err := readTxs(env, txs, *txFile)
+	if err != nil {
+		return err
+	}
+	if len(rawTxs) != 1 {
+		return fmt.Errorf("expected one transaction, got %d", len(rawTxs))
+	}
+	arg := common.SendPrivateTxArgs{Tx: rawTxs[0]}
+	if *maxBlock != "" {
+		if arg.MaxBlockNumber, err = resolveBlock(ctx, env, *maxBlock); err != nil {
+			return err
+		}
+	}
+	if *fast {
+		arg.Preferences = &common.Preferences{Fast: true}
+	}
+	res, err := fbc.SendPrivateTransaction(ctx, []common.SendPrivateTxArgs{arg})
+	if err != nil {
+		return err
+	}
+	return printResult(env, res)
+}
+
+func runCancelPrivateTx(ctx context.Context, env *cmdEnv, args []string) error {
+	fs := newFlagSet("cancel-private-tx", env)
+	txHash := fs.String("tx-hash", "", "hash of the private tx")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *txHash == "" {
+		return errors.New("missing --tx-hash")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, env.timeout)
+	defer cancel()
+	fbc, err := env.client()
+	if err != nil {
+		return err
+	}
+	res, err := fbc.CancelPrivateTransaction(ctx, []common.CancelPrivateTxArgs{{
+		TxHash: *txHash,
+	}})
+	if err != nil {
+		return err
+	}
+	return printResult(env, res)
+}
+
+func runBundleStats(ctx context.Context, env *cmdEnv, args []string) error {
+	fs := newFlagSet("bundle-stats", env)
+	bundleHash := fs.String("bundle-hash", "", "hash returned by send-bundle")
+	block := fs.String("block", "", "block the bundle targeted: hex or decimal")
+	v2 := fs.Bool("v2", false, "use flashbots_getBundleStatsV2")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *bundleHash == "" {
+		return errors.New("missing --bundle-hash")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, env.timeout)
+	defer cancel()
+	fbc, err := env.client()
+	if err != nil {
+		return err
+	}
+	blockNum, err := resolveBlock(ctx, env, *block)
+	if err != nil {
+		return err
+	}
+	arg := []common.BundleStatsArgs{{
+		BundleHash:  *bundleHash,
+		BlockNumber: blockNum,
+	}}
+	if *v2 {
+		res, err := fbc.BundleStatsV2(ctx, arg)
+		if err != nil {
+			return err
+		}
+		return printResult(env, res)
+	}
+	res, err := fbc.BundleStats(ctx, arg)
+	if err != nil {
+		return err
+	}
+	return printResult(env, res)
+}
+
+func runUserStats(ctx context.Context, env *cmdEnv, args []string) error {
+	fs := newFlagSet("user-stats", env)
+	block := fs.String("block", "+0", "recent block: hex, decimal or +N from the current head")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, env.timeout)
+	defer cancel()
+	fbc, err := env.client()
+	if err != nil {
+		return err
+	}
+	blockNum, err := resolveBlock(ctx, env, *block)
+	if err != nil {
+		return err
+	}
+	res, err := fbc.UserStats(ctx, []common.UserStatsArgs{{
+		BlockNumber: blockNum,
+	}})
+	if err != nil {
+		return err
+	}
+	return printResult(env, res)
+}
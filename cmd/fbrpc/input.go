@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// stringList is a flag that can be repeated.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// readTxs collects raw txs from --tx flags and --tx-file, where "-" reads stdin. A file holds
+// either a JSON array of strings or whitespace separated hex strings.
+func readTxs(env *cmdEnv, flagTxs []string, file string) ([]string, error) {
+	txs := append([]string(nil), flagTxs...)
+	if file != "" {
+		var r io.Reader = env.stdin
+		if file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			r = f
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		content := strings.TrimSpace(string(b))
+		if strings.HasPrefix(content, "[") {
+			var fromJSON []string
+			if err = json.Unmarshal([]byte(content), &fromJSON); err != nil {
+				return nil, fmt.Errorf("invalid tx file: %w", err)
+			}
+			txs = append(txs, fromJSON...)
+		} else {
+			txs = append(txs, strings.Fields(content)...)
+		}
+	}
+	if len(txs) == 0 {
+		return nil, errors.New("no transactions, use --tx or --tx-file")
+	}
+	for i, tx := range txs {
+		if _, err := hexutil.Decode(tx); err != nil {
+			return nil, fmt.Errorf("tx %d: %w", i, err)
+		}
+	}
+	return txs, nil
+}
+
+// resolveBlock turns a hex, decimal or head-relative ("+N") block number into the hex
+// encoding expected by the relay.
+func resolveBlock(ctx context.Context, env *cmdEnv, block string) (string, error) {
+	switch {
+	case block == "":
+		return "", errors.New("missing --block")
+	case strings.HasPrefix(block, "+"):
+		offset, err := strconv.ParseUint(block[1:], 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid block offset %q", block)
+		}
+		if env.ethRPC == "" {
+			return "", errors.New("relative block numbers need --eth-rpc or $ETH_RPC_URL")
+		}
+		eth, err := ethclient.DialContext(ctx, env.ethRPC)
+		if err != nil {
+			return "", err
+		}
+		defer eth.Close()
+		head, err := eth.BlockNumber(ctx)
+		if err != nil {
+			return "", err
+		}
+		return hexutil.EncodeUint64(head + offset), nil
+	case strings.HasPrefix(block, "0x"):
+		n, err := hexutil.DecodeUint64(block)
+		if err != nil {
+			return "", fmt.Errorf("invalid block %q: %w", block, err)
+		}
+		return hexutil.EncodeUint64(n), nil
+	default:
+		n, err := strconv.ParseUint(block, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid block %q", block)
+		}
+		return hexutil.EncodeUint64(n), nil
+	}
+}
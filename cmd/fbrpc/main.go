@@ -0,0 +1,126 @@
+// Command fbrpc sends bundles and private transactions to a Flashbots relay.
+//
+//	fbrpc <command> [flags]
+//
+// Run "fbrpc <command> -h" for the flags of a command.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/client"
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+)
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, env *cmdEnv, args []string) error
+}
+
+var commands = map[string]command{
+	"call-bundle":       {"simulate a bundle with eth_callBundle", runCallBundle},
+	"send-bundle":       {"submit a bundle with eth_sendBundle", runSendBundle},
+	"cancel-bundle":     {"cancel a bundle by replacement uuid", runCancelBundle},
+	"send-private-tx":   {"submit a private transaction", runSendPrivateTx},
+	"cancel-private-tx": {"cancel a private transaction", runCancelPrivateTx},
+	"bundle-stats":      {"show the relay stats of a bundle", runBundleStats},
+	"user-stats":        {"show the relay stats of the signer", runUserStats},
+	"decode-txs":        {"decode raw transactions without contacting the relay", runDecodeTxs},
+}
+
+// cmdEnv carries the flags shared by every command.
+type cmdEnv struct {
+	network   string
+	relay     string
+	signerKey string
+	ethRPC    string
+	output    string
+	timeout   time.Duration
+	stdin     io.Reader
+	stdout    io.Writer
+}
+
+func (env *cmdEnv) register(fs *flag.FlagSet) {
+	fs.StringVar(&env.network, "network", common.NetworkMainnet, "network profile: "+networkNames())
+	fs.StringVar(&env.relay, "relay", "", "relay URL, defaults to the relay of --network")
+	fs.StringVar(&env.signerKey, "signer-key", "", "hex private key used to sign requests, defaults to $SIGNER_PRIVATE_KEY")
+	fs.StringVar(&env.ethRPC, "eth-rpc", os.Getenv("ETH_RPC_URL"), "Ethereum node URL, needed for relative block numbers like +1")
+	fs.StringVar(&env.output, "output", "json", "output format: json or table")
+	fs.DurationVar(&env.timeout, "timeout", time.Second*10, "request timeout")
+}
+
+// client connects to --relay, or the relay of --network, and checks txs against the chain id of --network.
+func (env *cmdEnv) client() (*client.FlashbotsClient, error) {
+	network, err := common.LookupNetwork(env.network)
+	if err != nil {
+		return nil, err
+	}
+	relay := env.relay
+	if relay == "" {
+		relay = network.RelayURL
+	}
+	var fbc *client.FlashbotsClient
+	if env.signerKey == "" {
+		fbc = client.NewFlashbotsClient(relay)
+	} else {
+		fbc = client.NewFlashbotsClientWithSigner(relay, strings.TrimPrefix(env.signerKey, "0x"))
+	}
+	fbc.SetNetwork(&network)
+	return fbc, nil
+}
+
+func networkNames() string {
+	var names []string
+	for _, n := range common.Networks() {
+		names = append(names, n.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	if name == "-h" || name == "--help" || name == "help" {
+		usage(os.Stdout)
+		return
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "fbrpc: unknown command %q\n\n", name)
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+	env := &cmdEnv{stdin: os.Stdin, stdout: os.Stdout}
+	if err := cmd.run(context.Background(), env, os.Args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "fbrpc %s: %v\n", name, err)
+		os.Exit(1)
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: fbrpc <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-18s %s\n", name, commands[name].usage)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/util"
+)
+
+func printResult(env *cmdEnv, v interface{}) error {
+	switch env.output {
+	case "json":
+		enc := json.NewEncoder(env.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "table":
+		return printTable(env.stdout, v)
+	default:
+		return fmt.Errorf("unknown output format %q", env.output)
+	}
+}
+
+// printTable prints the top level fields of v as rows, nested values stay JSON encoded.
+func printTable(w io.Writer, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(b, &fields); err != nil {
+		// not an object, print it as a single value
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tVALUE")
+	for _, k := range keys {
+		value := string(fields[k])
+		var s string
+		if json.Unmarshal(fields[k], &s) == nil {
+			value = s
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", k, value)
+	}
+	return tw.Flush()
+}
+
+// printDecodedTxs prints one row per tx in table mode.
+func printDecodedTxs(env *cmdEnv, decoded []*util.DecodedTx) error {
+	if env.output != "table" {
+		return printResult(env, decoded)
+	}
+	tw := tabwriter.NewWriter(env.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "INDEX\tHASH\tTYPE\tFROM\tTO\tNONCE\tVALUE\tGAS\tGAS_USED\tERROR")
+	for _, d := range decoded {
+		to := "create"
+		if d.To != nil {
+			to = d.To.Hex()
+		}
+		gasUsed, simErr := "-", ""
+		if sim := d.Simulation; sim != nil {
+			gasUsed = strconv.FormatUint(sim.GasUsed, 10)
+			if sim.Error != nil {
+				simErr = *sim.Error
+			}
+			if sim.Revert != nil {
+				simErr = strings.TrimSpace(simErr + " " + *sim.Revert)
+			}
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%d\t%s\t%d\t%s\t%s\n",
+			d.Index, d.Hash.Hex(), d.TypeName(), d.From.Hex(), to, d.Nonce, d.Value, d.Gas, gasUsed, simErr)
+	}
+	return tw.Flush()
+}
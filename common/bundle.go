@@ -0,0 +1,295 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func (r *CallBundleResponse) UnmarshalJSON(input []byte) error {
+	var dec struct {
+		Results           []TxSimulationResponse `json:"results"`
+		CoinbaseDiff      json.RawMessage        `json:"coinbaseDiff"`
+		GasFees           json.RawMessage        `json:"gasFees"`
+		EthSentToCoinbase json.RawMessage        `json:"ethSentToCoinbase"`
+		BundleGasPrice    json.RawMessage        `json:"bundleGasPrice"`
+		TotalGasUsed      json.RawMessage        `json:"totalGasUsed"`
+		StateBlockNumber  json.RawMessage        `json:"stateBlockNumber"`
+		BundleHash        json.RawMessage        `json:"bundleHash"`
+	}
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	var err error
+	res := CallBundleResponse{Results: dec.Results}
+	if res.CoinbaseDiff, err = decodeBig("coinbaseDiff", dec.CoinbaseDiff); err != nil {
+		return err
+	}
+	if res.GasFees, err = decodeBig("gasFees", dec.GasFees); err != nil {
+		return err
+	}
+	if res.EthSentToCoinbase, err = decodeBig("ethSentToCoinbase", dec.EthSentToCoinbase); err != nil {
+		return err
+	}
+	if res.BundleGasPrice, err = decodeBig("bundleGasPrice", dec.BundleGasPrice); err != nil {
+		return err
+	}
+	if res.TotalGasUsed, err = decodeUint64("totalGasUsed", dec.TotalGasUsed); err != nil {
+		return err
+	}
+	if res.StateBlockNumber, err = decodeUint64("stateBlockNumber", dec.StateBlockNumber); err != nil {
+		return err
+	}
+	if res.BundleHash, err = decodeHash("bundleHash", dec.BundleHash); err != nil {
+		return err
+	}
+	res.Raw = append(json.RawMessage(nil), input...)
+	*r = res
+	return nil
+}
+
+func (r *TxSimulationResponse) UnmarshalJSON(input []byte) error {
+	var dec struct {
+		TxHash            json.RawMessage `json:"txHash"`
+		GasUsed           json.RawMessage `json:"gasUsed"`
+		GasPrice          json.RawMessage `json:"gasPrice"`
+		GasFees           json.RawMessage `json:"gasFees"`
+		FromAddress       json.RawMessage `json:"fromAddress"`
+		ToAddress         json.RawMessage `json:"toAddress"`
+		CoinbaseDiff      json.RawMessage `json:"coinbaseDiff"`
+		EthSentToCoinbase json.RawMessage `json:"ethSentToCoinbase"`
+		Error             *string         `json:"error"`
+		Revert            *string         `json:"revert"`
+		Value             json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	var err error
+	res := TxSimulationResponse{Error: dec.Error, Revert: dec.Revert}
+	if res.TxHash, err = decodeHash("txHash", dec.TxHash); err != nil {
+		return err
+	}
+	if res.GasUsed, err = decodeUint64("gasUsed", dec.GasUsed); err != nil {
+		return err
+	}
+	if res.GasPrice, err = decodeBig("gasPrice", dec.GasPrice); err != nil {
+		return err
+	}
+	if res.GasFees, err = decodeBig("gasFees", dec.GasFees); err != nil {
+		return err
+	}
+	if res.FromAddress, err = decodeAddress("fromAddress", dec.FromAddress); err != nil {
+		return err
+	}
+	if res.ToAddress, err = decodeAddress("toAddress", dec.ToAddress); err != nil {
+		return err
+	}
+	if res.CoinbaseDiff, err = decodeBig("coinbaseDiff", dec.CoinbaseDiff); err != nil {
+		return err
+	}
+	if res.EthSentToCoinbase, err = decodeBig("ethSentToCoinbase", dec.EthSentToCoinbase); err != nil {
+		return err
+	}
+	if res.Value, err = decodeBytes("value", dec.Value); err != nil {
+		return err
+	}
+	res.Raw = append(json.RawMessage(nil), input...)
+	*r = res
+	return nil
+}
+
+// MarshalJSON encodes r the way the relay sends it, so decoded responses can be stored
+// or forwarded: big numbers as decimal strings, gas and block numbers as JSON numbers.
+func (r CallBundleResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Results           []TxSimulationResponse `json:"results"`
+		CoinbaseDiff      *string                `json:"coinbaseDiff,omitempty"`
+		GasFees           *string                `json:"gasFees,omitempty"`
+		EthSentToCoinbase *string                `json:"ethSentToCoinbase,omitempty"`
+		BundleGasPrice    *string                `json:"bundleGasPrice,omitempty"`
+		TotalGasUsed      uint64                 `json:"totalGasUsed"`
+		StateBlockNumber  uint64                 `json:"stateBlockNumber"`
+		BundleHash        gethcommon.Hash        `json:"bundleHash"`
+	}{
+		Results:           r.Results,
+		CoinbaseDiff:      encodeBig(r.CoinbaseDiff),
+		GasFees:           encodeBig(r.GasFees),
+		EthSentToCoinbase: encodeBig(r.EthSentToCoinbase),
+		BundleGasPrice:    encodeBig(r.BundleGasPrice),
+		TotalGasUsed:      r.TotalGasUsed,
+		StateBlockNumber:  r.StateBlockNumber,
+		BundleHash:        r.BundleHash,
+	})
+}
+
+// MarshalJSON encodes r the way the relay sends it, see CallBundleResponse.MarshalJSON.
+func (r TxSimulationResponse) MarshalJSON() ([]byte, error) {
+	var value *hexutil.Bytes
+	if r.Value != nil {
+		value = &r.Value
+	}
+	return json.Marshal(struct {
+		TxHash            gethcommon.Hash `json:"txHash"`
+		GasUsed           uint64          `json:"gasUsed"`
+		GasPrice          *string         `json:"gasPrice,omitempty"`
+		GasFees           *string         `json:"gasFees,omitempty"`
+		FromAddress       *string         `json:"fromAddress,omitempty"`
+		ToAddress         *string         `json:"toAddress,omitempty"`
+		CoinbaseDiff      *string         `json:"coinbaseDiff,omitempty"`
+		EthSentToCoinbase *string         `json:"ethSentToCoinbase,omitempty"`
+		Error             *string         `json:"error,omitempty"`
+		Revert            *string         `json:"revert,omitempty"`
+		Value             *hexutil.Bytes  `json:"value,omitempty"`
+	}{
+		TxHash:            r.TxHash,
+		GasUsed:           r.GasUsed,
+		GasPrice:          encodeBig(r.GasPrice),
+		GasFees:           encodeBig(r.GasFees),
+		FromAddress:       encodeAddress(r.FromAddress),
+		ToAddress:         encodeAddress(r.ToAddress),
+		CoinbaseDiff:      encodeBig(r.CoinbaseDiff),
+		EthSentToCoinbase: encodeBig(r.EthSentToCoinbase),
+		Error:             r.Error,
+		Revert:            r.Revert,
+		Value:             value,
+	})
+}
+
+func encodeBig(n *big.Int) *string {
+	if n == nil {
+		return nil
+	}
+	s := n.String()
+	return &s
+}
+
+func encodeAddress(addr *gethcommon.Address) *string {
+	if addr == nil {
+		return nil
+	}
+	s := addr.Hex()
+	return &s
+}
+
+// unquote returns the string content of raw, which may be a JSON string or number.
+// ok is false for missing and null values.
+func unquote(raw json.RawMessage) (s string, ok bool, err error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return "", false, nil
+	}
+	if raw[0] == '"' {
+		if err = json.Unmarshal(raw, &s); err != nil {
+			return "", false, err
+		}
+		return s, s != "", nil
+	}
+	return string(raw), true, nil
+}
+
+// decodeBig accepts the decimal and 0x-prefixed hex encodings used by the relay, quoted or not.
+func decodeBig(field string, raw json.RawMessage) (*big.Int, error) {
+	s, ok, err := unquote(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", field, err)
+	}
+	if !ok {
+		return nil, nil
+	}
+	n := new(big.Int)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		_, ok = n.SetString(s[2:], 16)
+	} else {
+		_, ok = n.SetString(s, 10)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%s: invalid number %q", field, s)
+	}
+	return n, nil
+}
+
+func decodeUint64(field string, raw json.RawMessage) (uint64, error) {
+	n, err := decodeBig(field, raw)
+	if err != nil || n == nil {
+		return 0, err
+	}
+	if n.Sign() < 0 || !n.IsUint64() {
+		return 0, fmt.Errorf("%s: %s out of range", field, n)
+	}
+	return n.Uint64(), nil
+}
+
+func decodeHash(field string, raw json.RawMessage) (gethcommon.Hash, error) {
+	s, ok, err := unquote(raw)
+	if err != nil || !ok {
+		return gethcommon.Hash{}, err
+	}
+	b, err := hexutil.Decode(s)
+	if err != nil || len(b) != gethcommon.HashLength {
+		return gethcommon.Hash{}, fmt.Errorf("%s: invalid hash %q", field, s)
+	}
+	return gethcommon.BytesToHash(b), nil
+}
+
+func decodeAddress(field string, raw json.RawMessage) (*gethcommon.Address, error) {
+	s, ok, err := unquote(raw)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if !gethcommon.IsHexAddress(s) {
+		return nil, fmt.Errorf("%s: invalid address %q", field, s)
+	}
+	addr := gethcommon.HexToAddress(s)
+	return &addr, nil
+}
+
+func decodeBytes(field string, raw json.RawMessage) (hexutil.Bytes, error) {
+	s, ok, err := unquote(raw)
+	if err != nil || !ok {
+		return nil, err
+	}
+	b, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", field, err)
+	}
+	return b, nil
+}
+
+// orZero returns a copy of n, or zero when the relay omitted the field.
+func orZero(n *big.Int) *big.Int {
+	if n == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(n)
+}
+
+// Deprecated: use the CoinbaseDiff field, which is decoded on unmarshal.
+func (r *CallBundleResponse) CoinbaseDiffInt() (*big.Int, error) {
+	return orZero(r.CoinbaseDiff), nil
+}
+
+// Deprecated: use the EthSentToCoinbase field, which is decoded on unmarshal.
+func (r *CallBundleResponse) EthSentToCoinbaseInt() (*big.Int, error) {
+	return orZero(r.EthSentToCoinbase), nil
+}
+
+// Deprecated: use the GasFees field, which is decoded on unmarshal.
+func (r *CallBundleResponse) GasFeesInt() (*big.Int, error) {
+	return orZero(r.GasFees), nil
+}
+
+// Deprecated: use the BundleGasPrice field, which is decoded on unmarshal.
+func (r *CallBundleResponse) BundleGasPriceInt() (*big.Int, error) {
+	return orZero(r.BundleGasPrice), nil
+}
+
+// Deprecated: use the TotalGasUsed field.
+func (r *CallBundleResponse) TotalGasUsedInt() *big.Int {
+	return new(big.Int).SetUint64(r.TotalGasUsed)
+}
@@ -0,0 +1,233 @@
+package common
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// relayCallBundle is an eth_callBundle result as returned by the Flashbots relay.
+const relayCallBundle = `{
+	"bundleGasPrice": "476190476193",
+	"bundleHash": "0x73b1e258c7a42fd0230b2fd05529c5d4b6fcb66c227783f8bece8aeacdd1db2e",
+	"coinbaseDiff": "20000000000126000",
+	"ethSentToCoinbase": "20000000000000000",
+	"gasFees": "126000",
+	"results": [
+		{
+			"coinbaseDiff": "10000000000063000",
+			"ethSentToCoinbase": "10000000000000000",
+			"fromAddress": "0x02A727155aeF8609c9f7F2179b2a1f560B39F5A0",
+			"gasFees": "63000",
+			"gasPrice": "476190476193",
+			"gasUsed": 21000,
+			"toAddress": "0x73625f59CAdc5009Cb458B751b3E7b6b48C06f2C",
+			"txHash": "0x669b4704a7d993a946cdd6e2f95233f308ce0c4649d2e04944e8299efcaa098a",
+			"value": "0x"
+		},
+		{
+			"coinbaseDiff": "10000000000063000",
+			"ethSentToCoinbase": "10000000000000000",
+			"fromAddress": "0x02A727155aeF8609c9f7F2179b2a1f560B39F5A0",
+			"gasFees": "63000",
+			"gasPrice": "476190476193",
+			"gasUsed": 21000,
+			"toAddress": "0x73625f59CAdc5009Cb458B751b3E7b6b48C06f2C",
+			"txHash": "0xa839ee83465657cac01adc1d50d96c1b586ed498120a84a64749c0034b4f19fa",
+			"error": "execution reverted",
+			"revert": "0x08c379a0",
+			"value": "0x"
+		}
+	],
+	"stateBlockNumber": 5221585,
+	"totalGasUsed": 42000
+}`
+
+// relayCallBundleHex carries the same totals as hex quantities, as some builders send them.
+const relayCallBundleHex = `{
+	"bundleGasPrice": "0x6edf2a07a1",
+	"bundleHash": "0x73b1e258c7a42fd0230b2fd05529c5d4b6fcb66c227783f8bece8aeacdd1db2e",
+	"coinbaseDiff": "0x470de4df83ec30",
+	"ethSentToCoinbase": "0x470de4df820000",
+	"gasFees": null,
+	"results": [],
+	"stateBlockNumber": "0x4facd1",
+	"totalGasUsed": "0xa410"
+}`
+
+func TestCallBundleResponseJSON(t *testing.T) {
+	var res CallBundleResponse
+	if err := json.Unmarshal([]byte(relayCallBundle), &res); err != nil {
+		t.Fatal(err)
+	}
+	from := gethcommon.HexToAddress("0x02A727155aeF8609c9f7F2179b2a1f560B39F5A0")
+	if res.CoinbaseDiff.String() != "20000000000126000" || res.GasFees.Int64() != 126000 ||
+		res.EthSentToCoinbase.String() != "20000000000000000" || res.BundleGasPrice.Int64() != 476190476193 ||
+		res.TotalGasUsed != 42000 || res.StateBlockNumber != 5221585 ||
+		res.BundleHash != gethcommon.HexToHash("0x73b1e258c7a42fd0230b2fd05529c5d4b6fcb66c227783f8bece8aeacdd1db2e") {
+		t.Fatalf("decoded bundle = %+v", res)
+	}
+	if len(res.Results) != 2 {
+		t.Fatalf("decoded %d results, want 2", len(res.Results))
+	}
+	tx := res.Results[1]
+	if tx.GasUsed != 21000 || tx.GasPrice.Int64() != 476190476193 || tx.FromAddress == nil || *tx.FromAddress != from ||
+		tx.Error == nil || *tx.Error != "execution reverted" || tx.Revert == nil || len(tx.Value) != 0 {
+		t.Fatalf("decoded tx = %+v", tx)
+	}
+	if string(res.Raw) != relayCallBundle {
+		t.Fatal("Raw does not hold the relay response")
+	}
+
+	// re-encoding must give the relay format back
+	out, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want interface{}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(relayCallBundle), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("json.Marshal() =\n%s\nwant the relay response", out)
+	}
+
+	var again CallBundleResponse
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Fatal(err)
+	}
+	again.Raw, res.Raw = nil, nil
+	for i := range res.Results {
+		again.Results[i].Raw, res.Results[i].Raw = nil, nil
+	}
+	if !reflect.DeepEqual(again, res) {
+		t.Fatalf("round trip = %+v, want %+v", again, res)
+	}
+}
+
+func TestCallBundleResponseHexJSON(t *testing.T) {
+	var res CallBundleResponse
+	if err := json.Unmarshal([]byte(relayCallBundleHex), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.CoinbaseDiff.String() != "20000000000126000" || res.GasFees != nil ||
+		res.BundleGasPrice.Int64() != 476190476193 || res.TotalGasUsed != 42000 || res.StateBlockNumber != 5221585 {
+		t.Fatalf("decoded bundle = %+v", res)
+	}
+	out, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"results":[],"coinbaseDiff":"20000000000126000","ethSentToCoinbase":"20000000000000000",` +
+		`"bundleGasPrice":"476190476193","totalGasUsed":42000,"stateBlockNumber":5221585,` +
+		`"bundleHash":"0x73b1e258c7a42fd0230b2fd05529c5d4b6fcb66c227783f8bece8aeacdd1db2e"}`
+	if string(out) != want {
+		t.Fatalf("json.Marshal() =\n%s\nwant\n%s", out, want)
+	}
+}
+
+func TestCallBundleResponseDecodeErrors(t *testing.T) {
+	tests := []string{
+		`{"coinbaseDiff":"12abc"}`,
+		`{"coinbaseDiff":"0xzz"}`,
+		`{"totalGasUsed":-1}`,
+		`{"totalGasUsed":"18446744073709551616"}`,
+		`{"bundleHash":"0x1234"}`,
+		`{"results":[{"txHash":"0x669b"}]}`,
+		`{"results":[{"fromAddress":"0x02A7"}]}`,
+		`{"results":[{"value":"0xzz"}]}`,
+		`{"results":[{"gasUsed":1.5}]}`,
+	}
+	for _, input := range tests {
+		var res CallBundleResponse
+		if err := json.Unmarshal([]byte(input), &res); err == nil {
+			t.Errorf("json.Unmarshal(%s) succeeded, want error", input)
+		}
+	}
+}
+
+func TestDecodeBig(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    *big.Int
+		wantErr bool
+	}{
+		{`"20000000000126000"`, big.NewInt(20000000000126000), false},
+		{`"0x470de4df83ec30"`, big.NewInt(20000000000126000), false},
+		{`"0X10"`, big.NewInt(16), false},
+		{`21000`, big.NewInt(21000), false},
+		{`"0"`, big.NewInt(0), false},
+		{`null`, nil, false},
+		{`""`, nil, false},
+		{``, nil, false},
+		{`"1e18"`, nil, true},
+		{`"0x"`, nil, true},
+		{`"-"`, nil, true},
+		{`"unterminated`, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := decodeBig("field", json.RawMessage(tt.raw))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("decodeBig(%s) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if (got == nil) != (tt.want == nil) || (got != nil && got.Cmp(tt.want) != 0) {
+			t.Errorf("decodeBig(%s) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeHash(t *testing.T) {
+	hash := "0x669b4704a7d993a946cdd6e2f95233f308ce0c4649d2e04944e8299efcaa098a"
+	tests := []struct {
+		raw     string
+		want    gethcommon.Hash
+		wantErr bool
+	}{
+		{`"` + hash + `"`, gethcommon.HexToHash(hash), false},
+		{`null`, gethcommon.Hash{}, false},
+		{`""`, gethcommon.Hash{}, false},
+		{`"` + hash[2:] + `"`, gethcommon.Hash{}, true},
+		{`"0x669b"`, gethcommon.Hash{}, true},
+		{`"` + hash + `00"`, gethcommon.Hash{}, true},
+		{`12`, gethcommon.Hash{}, true},
+	}
+	for _, tt := range tests {
+		got, err := decodeHash("field", json.RawMessage(tt.raw))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("decodeHash(%s) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("decodeHash(%s) = %s, want %s", tt.raw, got.Hex(), tt.want.Hex())
+		}
+	}
+}
+
+func TestDeprecatedAccessors(t *testing.T) {
+	var res CallBundleResponse
+	if err := json.Unmarshal([]byte(relayCallBundleHex), &res); err != nil {
+		t.Fatal(err)
+	}
+	diff, err := res.CoinbaseDiffInt()
+	if err != nil || diff.Cmp(res.CoinbaseDiff) != 0 {
+		t.Fatalf("CoinbaseDiffInt() = %v, %v", diff, err)
+	}
+	// the copy must not alias the field
+	diff.SetInt64(0)
+	if res.CoinbaseDiff.Sign() == 0 {
+		t.Fatal("CoinbaseDiffInt() aliases CoinbaseDiff")
+	}
+	if fees, err := res.GasFeesInt(); err != nil || fees.Sign() != 0 {
+		t.Fatalf("GasFeesInt() = %v, %v, want 0 for a null field", fees, err)
+	}
+	if gas := res.TotalGasUsedInt(); gas.Uint64() != res.TotalGasUsed {
+		t.Fatalf("TotalGasUsedInt() = %v", gas)
+	}
+}
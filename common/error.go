@@ -1,18 +1,101 @@
 package common
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
 
+// HTTPError is returned for a non-2xx response.
 type HTTPError struct {
+	Method     string // JSON-RPC method of the request, if any
+	URL        string // endpoint, with any password redacted
 	StatusCode int
 	Status     string
-	Body       []byte
+	Header     http.Header // response headers, e.g. Retry-After
+	Body       []byte      // preview of the body
+	Truncated  bool        // the body was longer than the preview
+	// RPCError is the JSON-RPC error carried by the body, as relays answer invalid
+	// requests with a 400 and a regular JSON-RPC error.
+	RPCError *JSONError
 }
 
 func (err HTTPError) Error() string {
-	if len(err.Body) == 0 {
-		return err.Status
+	msg := err.Status
+	if err.Method != "" {
+		msg = err.Method + ": " + msg
+	}
+	switch {
+	case err.RPCError != nil:
+		return fmt.Sprintf("%s: %v (code %d)", msg, err.RPCError, err.RPCError.Code)
+	case len(err.Body) == 0:
+		return msg
+	default:
+		return fmt.Sprintf("%s: %s", msg, previewString(err.Body, err.Truncated))
+	}
+}
+
+// Unwrap exposes RPCError to errors.As.
+func (err HTTPError) Unwrap() error {
+	if err.RPCError == nil {
+		return nil
+	}
+	return err.RPCError
+}
+
+// Temporary reports whether the status signals a transient condition: a timeout,
+// rate limiting or an unavailable upstream.
+func (err HTTPError) Temporary() bool {
+	switch err.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// Retryable reports whether sending the same request again may succeed. Server errors
+// are retryable unless the relay explained the failure with a JSON-RPC error.
+func (err HTTPError) Retryable() bool {
+	if err.Temporary() {
+		return true
+	}
+	if err.RPCError != nil {
+		return false
+	}
+	return err.StatusCode >= 500 && err.StatusCode != http.StatusNotImplemented && err.StatusCode != http.StatusHTTPVersionNotSupported
+}
+
+// RetryAfter returns the wait requested by the Retry-After header, or zero.
+func (err HTTPError) RetryAfter() time.Duration {
+	v := err.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, e := strconv.Atoi(v); e == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, e := http.ParseTime(v); e == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// DecodeRPCError sets RPCError when Body holds a JSON-RPC response with an error.
+func (err *HTTPError) DecodeRPCError() {
+	var msg struct {
+		Error *JSONError `json:"error"`
+	}
+	if json.Unmarshal(err.Body, &msg) == nil && msg.Error != nil {
+		err.RPCError = msg.Error
 	}
-	return fmt.Sprintf("%v: %s", err.Status, err.Body)
 }
 
 type JSONError struct {
@@ -35,3 +118,27 @@ func (err *JSONError) ErrorCode() int {
 func (err *JSONError) ErrorData() interface{} {
 	return err.Data
 }
+
+// ContentTypeError is returned for a successful response that is not JSON, such as an
+// HTML page served by a proxy in front of the relay.
+type ContentTypeError struct {
+	ContentType string
+	StatusCode  int
+	Body        []byte // preview of the body
+	Truncated   bool
+}
+
+func (err *ContentTypeError) Error() string {
+	msg := fmt.Sprintf("unexpected content type %q (status %d), expected application/json", err.ContentType, err.StatusCode)
+	if len(err.Body) == 0 {
+		return msg
+	}
+	return fmt.Sprintf("%s: %s", msg, previewString(err.Body, err.Truncated))
+}
+
+func previewString(body []byte, truncated bool) string {
+	if truncated {
+		return string(body) + "...(truncated)"
+	}
+	return string(body)
+}
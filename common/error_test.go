@@ -0,0 +1,76 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHTTPError(t *testing.T) {
+	rpcBody := `{"jsonrpc":"2.0","id":1,"error":{"code":-32602,"message":"invalid bundle"}}`
+	retryAt := func(d time.Duration) http.Header {
+		return http.Header{"Retry-After": {time.Now().Add(d).UTC().Format(http.TimeFormat)}}
+	}
+	tests := []struct {
+		name          string
+		status        int
+		header        http.Header
+		body          string
+		wantRPCCode   int // zero when no JSON-RPC error is expected
+		wantRetryable bool
+		wantAfter     time.Duration // lower bound, RetryAfter may not exceed it by more than a second
+		wantMsg       string
+	}{
+		{name: "400 with json-rpc error", status: 400, body: rpcBody, wantRPCCode: -32602, wantMsg: "eth_sendBundle: 400 Bad Request: invalid bundle (code -32602)"},
+		{name: "400 plain", status: 400, body: "bad request", wantMsg: "eth_sendBundle: 400 Bad Request: bad request"},
+		{name: "429 retry after seconds", status: 429, header: http.Header{"Retry-After": {"3"}}, wantRetryable: true, wantAfter: 3 * time.Second},
+		{name: "429 retry after date", status: 429, header: retryAt(10 * time.Second), wantRetryable: true, wantAfter: 8 * time.Second},
+		{name: "429 retry after past date", status: 429, header: retryAt(-time.Minute), wantRetryable: true},
+		{name: "429 retry after invalid", status: 429, header: http.Header{"Retry-After": {"soon"}}, wantRetryable: true},
+		{name: "429 retry after negative", status: 429, header: http.Header{"Retry-After": {"-5"}}, wantRetryable: true},
+		{name: "429 with json-rpc error", status: 429, body: rpcBody, wantRPCCode: -32602, wantRetryable: true},
+		{name: "500", status: 500, body: "internal error", wantRetryable: true},
+		{name: "500 with json-rpc error", status: 500, body: rpcBody, wantRPCCode: -32602},
+		{name: "501", status: 501, wantMsg: "eth_sendBundle: 501 Not Implemented"},
+		{name: "502 with json-rpc error", status: 502, body: rpcBody, wantRPCCode: -32602, wantRetryable: true},
+		{name: "503", status: 503, wantRetryable: true},
+		{name: "504", status: 504, wantRetryable: true},
+		{name: "505", status: 505},
+		{name: "html body", status: 502, body: "<html><h1>502 Bad Gateway</h1></html>", wantRetryable: true,
+			wantMsg: "eth_sendBundle: 502 Bad Gateway: <html><h1>502 Bad Gateway</h1></html>"},
+		{name: "json without error", status: 500, body: `{"jsonrpc":"2.0","id":1,"result":null}`, wantRetryable: true},
+		{name: "truncated json", status: 400, body: rpcBody[:40]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := HTTPError{
+				Method:     "eth_sendBundle",
+				StatusCode: tt.status,
+				Status:     fmt.Sprintf("%d %s", tt.status, http.StatusText(tt.status)),
+				Header:     tt.header,
+				Body:       []byte(tt.body),
+			}
+			err.DecodeRPCError()
+
+			var rpcErr *JSONError
+			if tt.wantRPCCode != 0 {
+				if !errors.As(err, &rpcErr) || rpcErr.Code != tt.wantRPCCode {
+					t.Fatalf("RPCError = %v, want code %d", err.RPCError, tt.wantRPCCode)
+				}
+			} else if err.RPCError != nil || errors.As(err, &rpcErr) {
+				t.Fatalf("RPCError = %v, want nil", err.RPCError)
+			}
+			if got := err.Retryable(); got != tt.wantRetryable {
+				t.Fatalf("Retryable() = %v, want %v", got, tt.wantRetryable)
+			}
+			if got := err.RetryAfter(); got < tt.wantAfter || got > tt.wantAfter+2*time.Second || (tt.wantAfter == 0 && got != 0) {
+				t.Fatalf("RetryAfter() = %v, want %v", got, tt.wantAfter)
+			}
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Fatalf("Error() = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BuilderEndpoint is a block builder accepting eth_sendBundle directly.
+type BuilderEndpoint struct {
+	Name string
+	URL  string
+}
+
+// Network groups the endpoints of a chain served by Flashbots.
+type Network struct {
+	Name              string
+	ChainID           uint64
+	RelayURL          string
+	ProtectURL        string
+	MevShareStreamURL string
+	Builders          []BuilderEndpoint
+	GenesisTime       uint64 // beacon chain genesis, unix seconds
+	SecondsPerSlot    uint64 // DefaultSecondsPerSlot when zero
+}
+
+const (
+	NetworkMainnet = "mainnet"
+	NetworkSepolia = "sepolia"
+	NetworkHolesky = "holesky"
+)
+
+var (
+	networksMu sync.RWMutex // protects networks
+	networks   = map[string]Network{
+		NetworkMainnet: {
+			Name:              NetworkMainnet,
+			ChainID:           1,
+			RelayURL:          "https://relay.flashbots.net",
+			ProtectURL:        "https://rpc.flashbots.net",
+			MevShareStreamURL: "https://mev-share.flashbots.net",
+			GenesisTime:       1606824023,
+			SecondsPerSlot:    DefaultSecondsPerSlot,
+			Builders: []BuilderEndpoint{
+				{Name: BuilderFlashbots, URL: "https://relay.flashbots.net"},
+				{Name: BuilderBeaverbuild, URL: "https://rpc.beaverbuild.org"},
+				{Name: BuilderTitan, URL: "https://rpc.titanbuilder.xyz"},
+				{Name: BuilderRsync, URL: "https://rsync-builder.xyz"},
+				{Name: BuilderBuilder0x69, URL: "https://builder0x69.io"},
+				{Name: BuilderF1b, URL: "https://rpc.f1b.io"},
+			},
+		},
+		NetworkSepolia: {
+			Name:              NetworkSepolia,
+			ChainID:           11155111,
+			RelayURL:          "https://relay-sepolia.flashbots.net",
+			ProtectURL:        "https://rpc-sepolia.flashbots.net",
+			MevShareStreamURL: "https://mev-share-sepolia.flashbots.net",
+			GenesisTime:       1655733600,
+			SecondsPerSlot:    DefaultSecondsPerSlot,
+			Builders: []BuilderEndpoint{
+				{Name: BuilderFlashbots, URL: "https://relay-sepolia.flashbots.net"},
+			},
+		},
+		NetworkHolesky: {
+			Name:              NetworkHolesky,
+			ChainID:           17000,
+			RelayURL:          "https://relay-holesky.flashbots.net",
+			ProtectURL:        "https://rpc-holesky.flashbots.net",
+			MevShareStreamURL: "https://mev-share-holesky.flashbots.net",
+			GenesisTime:       1695902400,
+			SecondsPerSlot:    DefaultSecondsPerSlot,
+			Builders: []BuilderEndpoint{
+				{Name: BuilderFlashbots, URL: "https://relay-holesky.flashbots.net"},
+			},
+		},
+	}
+)
+
+// LookupNetwork returns the profile registered under name, case-insensitively.
+func LookupNetwork(name string) (Network, error) {
+	networksMu.RLock()
+	defer networksMu.RUnlock()
+	n, ok := networks[strings.ToLower(name)]
+	if !ok {
+		return Network{}, fmt.Errorf("unknown network %q", name)
+	}
+	return n, nil
+}
+
+// RegisterNetwork adds or replaces a profile, e.g. for a private relay.
+func RegisterNetwork(n Network) error {
+	if n.Name == "" {
+		return errors.New("network: empty name")
+	}
+	if n.ChainID == 0 {
+		return fmt.Errorf("network %s: missing chain id", n.Name)
+	}
+	if n.RelayURL == "" {
+		return fmt.Errorf("network %s: missing relay url", n.Name)
+	}
+	networksMu.Lock()
+	networks[strings.ToLower(n.Name)] = n
+	networksMu.Unlock()
+	return nil
+}
+
+// Networks lists the registered profiles sorted by name.
+func Networks() []Network {
+	networksMu.RLock()
+	defer networksMu.RUnlock()
+	list := make([]Network, 0, len(networks))
+	for _, n := range networks {
+		list = append(list, n)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// ChainIDMismatchError is returned when a tx is signed for another chain than the network.
+type ChainIDMismatchError struct {
+	Network string
+	Want    uint64
+	Got     uint64
+	Index   int
+}
+
+func (err *ChainIDMismatchError) Error() string {
+	return fmt.Sprintf("tx %d has chain id %d, network %s expects %d", err.Index, err.Got, err.Network, err.Want)
+}
+
+// CheckTxs verifies that every raw tx is signed for the chain of n. Unprotected legacy txs
+// are rejected since they can be replayed on any chain.
+func (n Network) CheckTxs(rawTxs []string) error {
+	if err := CheckTxTypes(rawTxs); err != nil {
+		return err
+	}
+	for i, rawTx := range rawTxs {
+		b, err := hexutil.Decode(rawTx)
+		if err != nil {
+			return fmt.Errorf("tx %d: %w", i, err)
+		}
+		tx := new(types.Transaction)
+		if err = tx.UnmarshalBinary(b); err != nil {
+			return fmt.Errorf("tx %d: %w", i, err)
+		}
+		if !tx.Protected() {
+			return fmt.Errorf("tx %d: not replay protected, network %s expects chain id %d", i, n.Name, n.ChainID)
+		}
+		if got := tx.ChainId(); !got.IsUint64() || got.Uint64() != n.ChainID {
+			return &ChainIDMismatchError{Network: n.Name, Want: n.ChainID, Got: got.Uint64(), Index: i}
+		}
+	}
+	return nil
+}
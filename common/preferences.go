@@ -0,0 +1,163 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// Hint selects which part of a private transaction is shared with searchers.
+type Hint string
+
+const (
+	HintCalldata         Hint = "calldata"
+	HintContractAddress  Hint = "contract_address"
+	HintFunctionSelector Hint = "function_selector"
+	HintLogs             Hint = "logs"
+	HintDefaultLogs      Hint = "default_logs"
+	HintHash             Hint = "hash"
+	HintTxHash           Hint = "tx_hash"
+)
+
+var knownHints = map[Hint]struct{}{
+	HintCalldata:         {},
+	HintContractAddress:  {},
+	HintFunctionSelector: {},
+	HintLogs:             {},
+	HintDefaultLogs:      {},
+	HintHash:             {},
+	HintTxHash:           {},
+}
+
+// Builder names as registered with the Flashbots relay.
+const (
+	BuilderFlashbots     = "flashbots"
+	BuilderF1b           = "f1b.io"
+	BuilderRsync         = "rsync"
+	BuilderBeaverbuild   = "beaverbuild.org"
+	BuilderBuilder0x69   = "builder0x69"
+	BuilderTitan         = "Titan"
+	BuilderEigenphi      = "EigenPhi"
+	BuilderBoba          = "boba-builder"
+	BuilderGambitLabs    = "Gambit Labs"
+	BuilderPayload       = "payload"
+	BuilderLoki          = "Loki"
+	BuilderBuildAI       = "BuildAI"
+	BuilderJetBuilder    = "JetBuilder"
+	BuilderTBuilder      = "tbuilder"
+	BuilderPenguinbuild  = "penguinbuild"
+	BuilderBobTheBuilder = "bobthebuilder"
+	BuilderBTCS          = "BTCS"
+	BuilderBloXroute     = "bloXroute"
+)
+
+// Preferences are the optional settings of eth_sendPrivateTransaction.
+type Preferences struct {
+	Fast     bool                 `json:"fast"` // optional. "fast" left for backwards compatibility; may be removed in a future version
+	Privacy  *PrivacyPreferences  `json:"privacy,omitempty"`
+	Validity *ValidityPreferences `json:"validity,omitempty"`
+
+	// Protect-style options, only understood by the Flashbots Protect RPC endpoint.
+	OriginID       string `json:"-"` // identifies the source of the order flow
+	UseMempool     bool   `json:"-"` // fall back to the public mempool if not included privately
+	CanRevert      bool   `json:"-"` // allow the tx to be included even when it reverts
+	MempoolRPC     string `json:"-"` // custom mempool RPC used when UseMempool is set
+	AuctionTimeout uint64 `json:"-"` // milliseconds to wait for MEV-Share backruns
+}
+
+type PrivacyPreferences struct {
+	Hints    []Hint   `json:"hints,omitempty"`    // data shared with searchers, nothing is shared when empty
+	Builders []string `json:"builders,omitempty"` // builders allowed to receive the tx, all when empty
+}
+
+type ValidityPreferences struct {
+	Refund []RefundConfig `json:"refund,omitempty"`
+}
+
+type RefundConfig struct {
+	Address gethcommon.Address `json:"address"`
+	Percent int                `json:"percent"` // share of the MEV-Share refund, 0-100
+}
+
+func (p *Preferences) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.Privacy != nil {
+		for _, h := range p.Privacy.Hints {
+			if _, ok := knownHints[h]; !ok {
+				return fmt.Errorf("preferences.privacy.hints: unknown hint %q", h)
+			}
+		}
+		seen := make(map[string]struct{}, len(p.Privacy.Builders))
+		for _, b := range p.Privacy.Builders {
+			if b == "" {
+				return errors.New("preferences.privacy.builders: empty builder name")
+			}
+			if _, ok := seen[b]; ok {
+				return fmt.Errorf("preferences.privacy.builders: duplicate builder %q", b)
+			}
+			seen[b] = struct{}{}
+		}
+	}
+	if p.Validity != nil {
+		total := 0
+		for i, r := range p.Validity.Refund {
+			if r.Address == (gethcommon.Address{}) {
+				return fmt.Errorf("preferences.validity.refund[%d].address: zero address", i)
+			}
+			if r.Percent < 0 || r.Percent > 100 {
+				return fmt.Errorf("preferences.validity.refund[%d].percent: %d out of range 0-100", i, r.Percent)
+			}
+			total += r.Percent
+		}
+		if total > 100 {
+			return fmt.Errorf("preferences.validity.refund: percentages sum to %d, more than 100", total)
+		}
+	}
+	if p.MempoolRPC != "" && !p.UseMempool {
+		return errors.New("preferences.mempoolRpc: requires useMempool")
+	}
+	return nil
+}
+
+// ProtectQuery encodes p as the query string understood by the Flashbots Protect RPC endpoint.
+// Fast mode is selected by the endpoint path and is not part of the query.
+func (p *Preferences) ProtectQuery() url.Values {
+	q := make(url.Values)
+	if p == nil {
+		return q
+	}
+	if p.Privacy != nil {
+		for _, h := range p.Privacy.Hints {
+			q.Add("hint", string(h))
+		}
+		for _, b := range p.Privacy.Builders {
+			q.Add("builder", b)
+		}
+	}
+	if p.Validity != nil {
+		for _, r := range p.Validity.Refund {
+			q.Add("refund", r.Address.Hex()+":"+strconv.Itoa(r.Percent))
+		}
+	}
+	if p.OriginID != "" {
+		q.Set("originId", p.OriginID)
+	}
+	if p.UseMempool {
+		q.Set("useMempool", "true")
+	}
+	if p.CanRevert {
+		q.Set("canRevert", "true")
+	}
+	if p.MempoolRPC != "" {
+		q.Set("mempoolRpc", p.MempoolRPC)
+	}
+	if p.AuctionTimeout > 0 {
+		q.Set("auctionTimeout", strconv.FormatUint(p.AuctionTimeout, 10))
+	}
+	return q
+}
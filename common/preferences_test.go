@@ -0,0 +1,91 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+func TestSendPrivateTxArgsJSON(t *testing.T) {
+	refund := gethcommon.HexToAddress("0x00000000000000000000000000000000000000aa")
+	tests := []struct {
+		name string
+		arg  SendPrivateTxArgs
+		want string
+	}{
+		{
+			name: "without preferences",
+			arg:  SendPrivateTxArgs{Tx: "0x01", MaxBlockNumber: "0x10"},
+			want: `{"tx":"0x01","maxBlockNumber":"0x10"}`,
+		},
+		{
+			name: "fast only",
+			arg:  SendPrivateTxArgs{Tx: "0x01", MaxBlockNumber: "0x10", Preferences: &Preferences{Fast: true}},
+			want: `{"tx":"0x01","maxBlockNumber":"0x10","preferences":{"fast":true}}`,
+		},
+		{
+			name: "privacy and validity",
+			arg: SendPrivateTxArgs{Tx: "0x01", MaxBlockNumber: "0x10", Preferences: &Preferences{
+				Privacy: &PrivacyPreferences{
+					Hints:    []Hint{HintCalldata, HintLogs},
+					Builders: []string{BuilderFlashbots, BuilderBeaverbuild},
+				},
+				Validity: &ValidityPreferences{Refund: []RefundConfig{{Address: refund, Percent: 90}}},
+			}},
+			want: `{"tx":"0x01","maxBlockNumber":"0x10","preferences":{"fast":false,` +
+				`"privacy":{"hints":["calldata","logs"],"builders":["flashbots","beaverbuild.org"]},` +
+				`"validity":{"refund":[{"address":"0x00000000000000000000000000000000000000aa","percent":90}]}}}`,
+		},
+		{
+			name: "protect options are not sent",
+			arg: SendPrivateTxArgs{Tx: "0x01", Preferences: &Preferences{
+				Privacy:    &PrivacyPreferences{},
+				OriginID:   "bot",
+				UseMempool: true,
+				CanRevert:  true,
+			}},
+			want: `{"tx":"0x01","maxBlockNumber":"","preferences":{"fast":false,"privacy":{}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.arg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Fatalf("json.Marshal() =\n%s\nwant\n%s", b, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreferencesValidate(t *testing.T) {
+	refund := gethcommon.HexToAddress("0x01")
+	tests := []struct {
+		name    string
+		prefs   *Preferences
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"empty", &Preferences{}, false},
+		{"known hints", &Preferences{Privacy: &PrivacyPreferences{Hints: []Hint{HintHash, HintTxHash}}}, false},
+		{"unknown hint", &Preferences{Privacy: &PrivacyPreferences{Hints: []Hint{"everything"}}}, true},
+		{"empty builder", &Preferences{Privacy: &PrivacyPreferences{Builders: []string{""}}}, true},
+		{"duplicate builder", &Preferences{Privacy: &PrivacyPreferences{Builders: []string{BuilderTitan, BuilderTitan}}}, true},
+		{"refund", &Preferences{Validity: &ValidityPreferences{Refund: []RefundConfig{{refund, 50}, {refund, 50}}}}, false},
+		{"refund zero address", &Preferences{Validity: &ValidityPreferences{Refund: []RefundConfig{{Percent: 50}}}}, true},
+		{"refund out of range", &Preferences{Validity: &ValidityPreferences{Refund: []RefundConfig{{refund, 101}}}}, true},
+		{"refund above total", &Preferences{Validity: &ValidityPreferences{Refund: []RefundConfig{{refund, 60}, {refund, 50}}}}, true},
+		{"mempool rpc without mempool", &Preferences{MempoolRPC: "https://rpc.example.org"}, true},
+		{"mempool rpc", &Preferences{UseMempool: true, MempoolRPC: "https://rpc.example.org"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.prefs.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package common
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultSecondsPerSlot is the beacon chain slot time of every Flashbots network.
+const DefaultSecondsPerSlot = 12
+
+// SlotClock maps wall time to beacon chain slots.
+type SlotClock struct {
+	Genesis      time.Time
+	SlotDuration time.Duration // DefaultSecondsPerSlot when not positive
+}
+
+func (c SlotClock) slotDuration() time.Duration {
+	if c.SlotDuration <= 0 {
+		return DefaultSecondsPerSlot * time.Second
+	}
+	return c.SlotDuration
+}
+
+// SlotAt returns the slot in progress at t, 0 before genesis.
+func (c SlotClock) SlotAt(t time.Time) uint64 {
+	if !t.After(c.Genesis) {
+		return 0
+	}
+	return uint64(t.Sub(c.Genesis) / c.slotDuration())
+}
+
+// SlotStart returns the time slot begins, which is also the deadline to reach its proposer.
+func (c SlotClock) SlotStart(slot uint64) time.Time {
+	return c.Genesis.Add(time.Duration(slot) * c.slotDuration())
+}
+
+func (c SlotClock) CurrentSlot() uint64 {
+	return c.SlotAt(time.Now())
+}
+
+// SlotClock returns the clock of n, which must have a GenesisTime.
+func (n Network) SlotClock() (SlotClock, error) {
+	if n.GenesisTime == 0 {
+		return SlotClock{}, fmt.Errorf("network %s: missing genesis time", n.Name)
+	}
+	secondsPerSlot := n.SecondsPerSlot
+	if secondsPerSlot == 0 {
+		secondsPerSlot = DefaultSecondsPerSlot
+	}
+	return SlotClock{
+		Genesis:      time.Unix(int64(n.GenesisTime), 0),
+		SlotDuration: time.Duration(secondsPerSlot) * time.Second,
+	}, nil
+}
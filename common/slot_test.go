@@ -0,0 +1,56 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlotClock(t *testing.T) {
+	genesis := time.Unix(1_600_000_000, 0)
+	tests := []struct {
+		name     string
+		duration time.Duration
+		at       time.Time
+		want     uint64
+	}{
+		{"before genesis", 12 * time.Second, genesis.Add(-time.Second), 0},
+		{"at genesis", 12 * time.Second, genesis, 0},
+		{"first slot", 12 * time.Second, genesis.Add(11 * time.Second), 0},
+		{"second slot", 12 * time.Second, genesis.Add(12 * time.Second), 1},
+		{"custom duration", 6 * time.Second, genesis.Add(61 * time.Second), 10},
+		{"zero duration", 0, genesis.Add(25 * time.Second), 2},
+		{"negative duration", -time.Second, genesis.Add(25 * time.Second), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := SlotClock{Genesis: genesis, SlotDuration: tt.duration}
+			if got := c.SlotAt(tt.at); got != tt.want {
+				t.Fatalf("SlotAt() = %d, want %d", got, tt.want)
+			}
+			if start := c.SlotStart(tt.want); start.After(tt.at) && tt.at.After(genesis) {
+				t.Fatalf("SlotStart(%d) = %v, after %v", tt.want, start, tt.at)
+			}
+		})
+	}
+}
+
+func TestNetworkSlotClock(t *testing.T) {
+	tests := []struct {
+		network Network
+		want    time.Duration
+		wantErr bool
+	}{
+		{Network{Name: "a", GenesisTime: 1}, 12 * time.Second, false},
+		{Network{Name: "b", GenesisTime: 1, SecondsPerSlot: 5}, 5 * time.Second, false},
+		{Network{Name: "c"}, 0, true},
+	}
+	for _, tt := range tests {
+		clock, err := tt.network.SlotClock()
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: SlotClock() error = %v, wantErr %v", tt.network.Name, err, tt.wantErr)
+		}
+		if clock.SlotDuration != tt.want {
+			t.Fatalf("%s: SlotDuration = %v, want %v", tt.network.Name, clock.SlotDuration, tt.want)
+		}
+	}
+}
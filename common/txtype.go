@@ -0,0 +1,64 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BlobTxType is the EIP-4844 tx type, which the go-ethereum version used by this module
+// can neither build nor decode.
+const BlobTxType = 0x03
+
+// ErrBlobTxUnsupported is returned for blob txs. Blob bundles need a go-ethereum release
+// with EIP-4844 support and an endpoint accepting sidecars.
+var ErrBlobTxUnsupported = errors.New("blob transactions (EIP-4844) are not supported")
+
+// UnsupportedTxTypeError is returned for a raw tx whose type cannot be bundled.
+type UnsupportedTxTypeError struct {
+	Type byte
+}
+
+func (err *UnsupportedTxTypeError) Error() string {
+	if err.Type == BlobTxType {
+		return ErrBlobTxUnsupported.Error()
+	}
+	return fmt.Sprintf("unsupported tx type %d", err.Type)
+}
+
+func (err *UnsupportedTxTypeError) Unwrap() error {
+	if err.Type == BlobTxType {
+		return ErrBlobTxUnsupported
+	}
+	return types.ErrTxTypeNotSupported
+}
+
+// CheckTxType accepts legacy, access list (EIP-2930) and dynamic fee (EIP-1559) txs.
+// It only reads the type byte of the binary encoded tx, decoding is left to the caller.
+func CheckTxType(b []byte) error {
+	// legacy txs are RLP lists, starting at 0xc0; typed txs start with their type
+	if len(b) == 0 || b[0] > 0x7f {
+		return nil
+	}
+	switch b[0] {
+	case types.AccessListTxType, types.DynamicFeeTxType:
+		return nil
+	}
+	return &UnsupportedTxTypeError{Type: b[0]}
+}
+
+// CheckTxTypes runs CheckTxType on hex encoded txs.
+func CheckTxTypes(rawTxs []string) error {
+	for i, rawTx := range rawTxs {
+		b, err := hexutil.Decode(rawTx)
+		if err != nil {
+			return fmt.Errorf("tx %d: %w", i, err)
+		}
+		if err = CheckTxType(b); err != nil {
+			return fmt.Errorf("tx %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestCheckTxType(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     []byte
+		wantErr error
+	}{
+		{"empty", nil, nil},
+		{"legacy", []byte{0xf8, 0x6b}, nil},
+		{"access list", []byte{types.AccessListTxType, 0xf8}, nil},
+		{"dynamic fee", []byte{types.DynamicFeeTxType, 0xf8}, nil},
+		{"blob", []byte{BlobTxType, 0xf8}, ErrBlobTxUnsupported},
+		{"unknown typed", []byte{0x7e, 0xf8}, types.ErrTxTypeNotSupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTxType(tt.raw)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("CheckTxType() error = %v, want %v", err, tt.wantErr)
+			}
+			var typeErr *UnsupportedTxTypeError
+			if err != nil && (!errors.As(err, &typeErr) || typeErr.Type != tt.raw[0]) {
+				t.Fatalf("CheckTxType() error = %#v, want UnsupportedTxTypeError", err)
+			}
+		})
+	}
+}
+
+func TestCheckTxTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		rawTxs  []string
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"valid", []string{"0x02f8", "0xf86b"}, false},
+		{"blob", []string{"0x02f8", "0x03f8"}, true},
+		{"not hex", []string{"02f8"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckTxTypes(tt.rawTxs); (err != nil) != tt.wantErr {
+				t.Fatalf("CheckTxTypes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
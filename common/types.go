@@ -2,6 +2,11 @@ package common
 
 import (
 	"encoding/json"
+	"errors"
+	"math/big"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 const (
@@ -40,15 +45,20 @@ type CallBundleArgs struct {
 }
 
 type SendBundleArgs struct {
-	Txs               []string // Array[String], A list of signed transactions to execute in an atomic bundle
-	BlockNumber       string   // String, a hex encoded block number for which this bundle is valid on
-	MinTimestamp      *uint64  // (Optional) Number, the minimum timestamp for which this bundle is valid, in seconds since the unix epoch
-	MaxTimestamp      *uint64  // (Optional) Number, the maximum timestamp for which this bundle is valid, in seconds since the unix epoch
-	RevertingTxHashes []string // (Optional) Array[String], A list of tx hashes that are allowed to revert
+	Txs               []string `json:"txs"`                         // Array[String], A list of signed transactions to execute in an atomic bundle
+	BlockNumber       string   `json:"blockNumber"`                 // String, a hex encoded block number for which this bundle is valid on
+	MinTimestamp      *uint64  `json:"minTimestamp,omitempty"`      // (Optional) Number, the minimum timestamp for which this bundle is valid, in seconds since the unix epoch
+	MaxTimestamp      *uint64  `json:"maxTimestamp,omitempty"`      // (Optional) Number, the maximum timestamp for which this bundle is valid, in seconds since the unix epoch
+	RevertingTxHashes []string `json:"revertingTxHashes,omitempty"` // (Optional) Array[String], A list of tx hashes that are allowed to revert
+	ReplacementUuid   string   `json:"replacementUuid,omitempty"`   // (Optional) String, UUIDv4 that can be used to replace or cancel this bundle
+}
+
+type CancelBundleArgs struct {
+	ReplacementUuid string `json:"replacementUuid"` // String, UUIDv4 passed to eth_sendBundle
 }
 
 type UserStatsArgs struct {
-	BlockNumber string // String, a hex encoded recent block number, in order to prevent replay attacks. Must be within 20 blocks of the current chain tip.
+	BlockNumber string `json:"blockNumber"` // String, a hex encoded recent block number, in order to prevent replay attacks. Must be within 20 blocks of the current chain tip.
 }
 
 type BundleStatsArgs struct {
@@ -57,40 +67,69 @@ type BundleStatsArgs struct {
 }
 
 type SendPrivateTxArgs struct {
-	Tx             string `json:"tx"`             // String, raw signed transaction
-	MaxBlockNumber string `json:"maxBlockNumber"` // Hex-encoded number string, optional. Highest block number in which the transaction should be included.
-	Preferences    *struct {
-		Fast bool `json:"fast"` // optional. "fast" left for backwards compatibility; may be removed in a future version
-	} `json:"preferences"`
+	Tx             string       `json:"tx"`             // String, raw signed transaction
+	MaxBlockNumber string       `json:"maxBlockNumber"` // Hex-encoded number string, optional. Highest block number in which the transaction should be included.
+	Preferences    *Preferences `json:"preferences,omitempty"`
+}
+
+func (a SendPrivateTxArgs) Validate() error {
+	if a.Tx == "" {
+		return errors.New("tx: empty raw transaction")
+	}
+	return a.Preferences.Validate()
+}
+
+// SendPrivateRawTxArgs is encoded positionally as [tx, preferences], the params of eth_sendPrivateRawTransaction.
+type SendPrivateRawTxArgs struct {
+	Tx          string       // String, raw signed transaction
+	Preferences *Preferences // optional
+}
+
+func (a SendPrivateRawTxArgs) MarshalJSON() ([]byte, error) {
+	if a.Preferences == nil {
+		return json.Marshal([]interface{}{a.Tx})
+	}
+	return json.Marshal([]interface{}{a.Tx, a.Preferences})
+}
+
+func (a SendPrivateRawTxArgs) Validate() error {
+	if a.Tx == "" {
+		return errors.New("tx: empty raw transaction")
+	}
+	return a.Preferences.Validate()
 }
 
 type CancelPrivateTxArgs struct {
 	TxHash string `json:"txHash"` // String, transaction hash of private tx to be cancelled
 }
 
+// CallBundleResponse numeric fields accept both decimal and hex encodings, see UnmarshalJSON.
+// MarshalJSON writes the relay encoding again.
 type CallBundleResponse struct {
 	Results           []TxSimulationResponse `json:"results"`
-	CoinbaseDiff      string                 `json:"coinbaseDiff"`
-	GasFees           *string                `json:"gasFees"`
-	EthSentToCoinbase string                 `json:"ethSentToCoinbase"`
-	BundleGasPrice    string                 `json:"bundleGasPrice"`
-	TotalGasUsed      int64                  `json:"totalGasUsed"`
-	StateBlockNumber  int64                  `json:"stateBlockNumber"`
-	BundleHash        string                 `json:"bundleHash"`
+	CoinbaseDiff      *big.Int               `json:"coinbaseDiff"`
+	GasFees           *big.Int               `json:"gasFees"`
+	EthSentToCoinbase *big.Int               `json:"ethSentToCoinbase"`
+	BundleGasPrice    *big.Int               `json:"bundleGasPrice"`
+	TotalGasUsed      uint64                 `json:"totalGasUsed"`
+	StateBlockNumber  uint64                 `json:"stateBlockNumber"`
+	BundleHash        gethcommon.Hash        `json:"bundleHash"`
+	Raw               json.RawMessage        `json:"-"` // response as received from the relay
 }
 
 type TxSimulationResponse struct {
-	TxHash            string  `json:"txHash"`
-	GasUsed           int64   `json:"gasUsed"`
-	GasPrice          *string `json:"gasPrice"`
-	GasFees           *string `json:"gasFees"`
-	FromAddress       *string `json:"fromAddress"`
-	ToAddress         *string `json:"toAddress"`
-	CoinbaseDiff      *string `json:"coinbaseDiff"`
-	EthSentToCoinbase *string `json:"ethSentToCoinbase"`
-	Error             *string `json:"error"`
-	Revert            *string `json:"revert"`
-	Value             *string `json:"value"`
+	TxHash            gethcommon.Hash     `json:"txHash"`
+	GasUsed           uint64              `json:"gasUsed"`
+	GasPrice          *big.Int            `json:"gasPrice"`
+	GasFees           *big.Int            `json:"gasFees"`
+	FromAddress       *gethcommon.Address `json:"fromAddress"`
+	ToAddress         *gethcommon.Address `json:"toAddress"`
+	CoinbaseDiff      *big.Int            `json:"coinbaseDiff"`
+	EthSentToCoinbase *big.Int            `json:"ethSentToCoinbase"`
+	Error             *string             `json:"error"`
+	Revert            *string             `json:"revert"`
+	Value             hexutil.Bytes       `json:"value"` // return data of the call
+	Raw               json.RawMessage     `json:"-"`
 }
 
 type SendBundleResponse struct {
@@ -105,6 +144,33 @@ type CancelPrivateTransactionResponse struct {
 	IsCancelled bool `json:"isCancelled"`
 }
 
+// TxStatusResponse is returned by the Flashbots Protect transaction status API.
+type TxStatusResponse struct {
+	Status         string `json:"status"` // PENDING, INCLUDED, FAILED, CANCELLED or UNKNOWN
+	Hash           string `json:"hash"`
+	MaxBlockNumber uint64 `json:"maxBlockNumber"`
+	Transaction    struct {
+		From                 string `json:"from"`
+		To                   string `json:"to"`
+		GasLimit             string `json:"gasLimit"`
+		MaxFeePerGas         string `json:"maxFeePerGas"`
+		MaxPriorityFeePerGas string `json:"maxPriorityFeePerGas"`
+		Nonce                string `json:"nonce"`
+		Value                string `json:"value"`
+	} `json:"transaction"`
+	FastMode      bool   `json:"fastMode"`
+	SeenInMempool bool   `json:"seenInMempool"`
+	SimError      string `json:"simError,omitempty"`
+}
+
+const (
+	TxStatusPending   = "PENDING"
+	TxStatusIncluded  = "INCLUDED"
+	TxStatusFailed    = "FAILED"
+	TxStatusCancelled = "CANCELLED"
+	TxStatusUnknown   = "UNKNOWN"
+)
+
 type BundleStatsResponse struct {
 	IsHighPriority bool   `json:"isHighPriority"`
 	IsSentToMiners bool   `json:"isSentToMiners"`
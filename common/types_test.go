@@ -0,0 +1,54 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestArgsJSON(t *testing.T) {
+	minTimestamp := uint64(1700000000)
+	tests := []struct {
+		name string
+		arg  interface{}
+		want string
+	}{
+		{
+			name: "send bundle",
+			arg:  SendBundleArgs{Txs: []string{"0x01", "0x02"}, BlockNumber: "0x10"},
+			want: `{"txs":["0x01","0x02"],"blockNumber":"0x10"}`,
+		},
+		{
+			name: "send bundle with options",
+			arg: SendBundleArgs{
+				Txs:               []string{"0x01"},
+				BlockNumber:       "0x10",
+				MinTimestamp:      &minTimestamp,
+				RevertingTxHashes: []string{"0xaa"},
+				ReplacementUuid:   "7c3b4f6e-0000-4000-8000-000000000000",
+			},
+			want: `{"txs":["0x01"],"blockNumber":"0x10","minTimestamp":1700000000,"revertingTxHashes":["0xaa"],` +
+				`"replacementUuid":"7c3b4f6e-0000-4000-8000-000000000000"}`,
+		},
+		{
+			name: "cancel bundle",
+			arg:  CancelBundleArgs{ReplacementUuid: "7c3b4f6e-0000-4000-8000-000000000000"},
+			want: `{"replacementUuid":"7c3b4f6e-0000-4000-8000-000000000000"}`,
+		},
+		{
+			name: "user stats",
+			arg:  UserStatsArgs{BlockNumber: "0x10"},
+			want: `{"blockNumber":"0x10"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.arg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.want {
+				t.Fatalf("json.Marshal() =\n%s\nwant\n%s", b, tt.want)
+			}
+		})
+	}
+}
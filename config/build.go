@@ -0,0 +1,88 @@
+package config
+
+import (
+	"time"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/client"
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+)
+
+// NewFlashbotsClient builds the client of the main relay.
+func (c *Config) NewFlashbotsClient() (*client.FlashbotsClient, error) {
+	relay := c.Relay
+	var network *common.Network
+	if c.Network != "" {
+		n, err := common.LookupNetwork(c.Network)
+		if err != nil {
+			return nil, err
+		}
+		network = &n
+		if relay == "" {
+			relay = n.RelayURL
+		}
+	}
+	fbc, err := c.newClient(relay, &c.Signer)
+	if err != nil {
+		return nil, err
+	}
+	fbc.SetNetwork(network)
+	return fbc, nil
+}
+
+// NewRelayClients builds a client for every entry of relays, keyed by name. Pass them to
+// client.WarmupAll to open their connections ahead of submission.
+func (c *Config) NewRelayClients() (map[string]*client.FlashbotsClient, error) {
+	clients := make(map[string]*client.FlashbotsClient, len(c.Relays))
+	for _, r := range c.Relays {
+		signer := r.Signer
+		if signer == nil {
+			signer = &c.Signer
+		}
+		fbc, err := c.newClient(r.URL, signer)
+		if err != nil {
+			return nil, err
+		}
+		clients[r.Name] = fbc
+	}
+	return clients, nil
+}
+
+func (c *Config) newClient(rawURL string, signerCfg *SignerConfig) (*client.FlashbotsClient, error) {
+	key, err := signerCfg.key()
+	if err != nil {
+		return nil, err
+	}
+	var signer client.Signer
+	if key == "" {
+		signer = client.NewSigner()
+	} else {
+		signer = client.NewSignerWithKey(key)
+	}
+	httpClient, err := client.DialHttpClientWithSigner(rawURL, signer)
+	if err != nil {
+		return nil, err
+	}
+	if c.HTTP.Timeout > 0 {
+		httpClient.SetTimeout(time.Duration(c.HTTP.Timeout))
+	}
+	if c.HTTP.tuned() {
+		err = httpClient.SetTransportOptions(client.TransportOptions{
+			ForceHTTP2:          c.HTTP.ForceHTTP2,
+			KeepAlive:           time.Duration(c.HTTP.KeepAlive),
+			IdleConnTimeout:     time.Duration(c.HTTP.IdleConnTimeout),
+			MaxIdleConnsPerHost: c.HTTP.MaxIdleConnsPerHost,
+			PreResolve:          c.HTTP.PreResolve,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	httpClient.SetMaxResponseSize(c.HTTP.MaxResponseSize)
+	httpClient.SetRetryPolicy(client.RetryPolicy{
+		MaxAttempts:    c.Retry.MaxAttempts,
+		InitialBackoff: time.Duration(c.Retry.InitialBackoff),
+		MaxBackoff:     time.Duration(c.Retry.MaxBackoff),
+	})
+	httpClient.SetRateLimit(c.RateLimit.RequestsPerSecond, c.RateLimit.Burst)
+	return client.NewFlashbotsClientWithHttpClient(httpClient), nil
+}
@@ -0,0 +1,182 @@
+// Package config builds FlashbotsClient instances from a YAML or TOML file.
+//
+//	network: mainnet
+//	signer:
+//	  private_key: ${SIGNER_PRIVATE_KEY}
+//	http:
+//	  timeout: 5s
+//	  force_http2: true
+//	  pre_resolve: true
+//	retry:
+//	  max_attempts: 3
+//	  initial_backoff: 200ms
+//	rate_limit:
+//	  requests_per_second: 10
+//	relays:
+//	  - name: beaverbuild
+//	    url: https://rpc.beaverbuild.org
+//
+// ${VAR} and ${VAR:-default} are replaced with environment variables before decoding.
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+type Config struct {
+	Network   string          `yaml:"network" toml:"network"`
+	Relay     string          `yaml:"relay" toml:"relay"` // overrides the relay of Network
+	Signer    SignerConfig    `yaml:"signer" toml:"signer"`
+	HTTP      HTTPConfig      `yaml:"http" toml:"http"`
+	Retry     RetryConfig     `yaml:"retry" toml:"retry"`
+	RateLimit RateLimitConfig `yaml:"rate_limit" toml:"rate_limit"`
+	Relays    []RelayConfig   `yaml:"relays" toml:"relays"` // additional relays or builders
+}
+
+type SignerConfig struct {
+	PrivateKey string `yaml:"private_key" toml:"private_key"`
+	KeyFile    string `yaml:"key_file" toml:"key_file"` // file holding the hex private key
+}
+
+type HTTPConfig struct {
+	Timeout             Duration `yaml:"timeout" toml:"timeout"`
+	MaxResponseSize     int64    `yaml:"max_response_size" toml:"max_response_size"` // bytes, client default when zero
+	ForceHTTP2          bool     `yaml:"force_http2" toml:"force_http2"`
+	KeepAlive           Duration `yaml:"keep_alive" toml:"keep_alive"`
+	IdleConnTimeout     Duration `yaml:"idle_conn_timeout" toml:"idle_conn_timeout"`
+	MaxIdleConnsPerHost int      `yaml:"max_idle_conns_per_host" toml:"max_idle_conns_per_host"`
+	PreResolve          bool     `yaml:"pre_resolve" toml:"pre_resolve"`
+}
+
+func (h *HTTPConfig) tuned() bool {
+	return h.ForceHTTP2 || h.KeepAlive != 0 || h.IdleConnTimeout != 0 || h.MaxIdleConnsPerHost != 0 || h.PreResolve
+}
+
+type RetryConfig struct {
+	MaxAttempts    int      `yaml:"max_attempts" toml:"max_attempts"`
+	InitialBackoff Duration `yaml:"initial_backoff" toml:"initial_backoff"`
+	MaxBackoff     Duration `yaml:"max_backoff" toml:"max_backoff"`
+}
+
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `yaml:"requests_per_second" toml:"requests_per_second"`
+	Burst             int     `yaml:"burst" toml:"burst"`
+}
+
+type RelayConfig struct {
+	Name string `yaml:"name" toml:"name"`
+	URL  string `yaml:"url" toml:"url"`
+	// Signer overrides the top level signer for this relay.
+	Signer *SignerConfig `yaml:"signer" toml:"signer"`
+}
+
+// Duration decodes Go duration strings such as "500ms" or "5s".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Load reads path, choosing the format from its extension.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var format string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = FormatYAML
+	case ".toml":
+		format = FormatTOML
+	default:
+		return nil, fmt.Errorf("config: unsupported file extension %q", filepath.Ext(path))
+	}
+	cfg, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse interpolates environment variables in data, decodes it and validates the result.
+// Unknown keys are rejected.
+func Parse(data []byte, format string) (*Config, error) {
+	data, err := interpolate(data)
+	if err != nil {
+		return nil, err
+	}
+	cfg := new(Config)
+	switch format {
+	case FormatYAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err = dec.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+	case FormatTOML:
+		md, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return nil, &ValidationError{Key: undecoded[0].String(), Msg: "unknown key"}
+		}
+	default:
+		return nil, fmt.Errorf("config: unsupported format %q", format)
+	}
+	if err = cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// interpolate replaces ${VAR} and ${VAR:-default}, reporting the line of unset variables.
+func interpolate(data []byte) ([]byte, error) {
+	lines := bytes.Split(data, []byte("\n"))
+	for i, line := range lines {
+		var missing string
+		lines[i] = envPattern.ReplaceAllFunc(line, func(m []byte) []byte {
+			sub := envPattern.FindSubmatch(m)
+			if v, ok := os.LookupEnv(string(sub[1])); ok {
+				return []byte(v)
+			}
+			if sub[2] != nil {
+				return sub[3]
+			}
+			if missing == "" {
+				missing = string(sub[1])
+			}
+			return m
+		})
+		if missing != "" {
+			return nil, fmt.Errorf("config: line %d: environment variable %s is not set", i+1, missing)
+		}
+	}
+	return bytes.Join(lines, []byte("\n")), nil
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testKey = "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+
+func TestParse(t *testing.T) {
+	t.Setenv("TEST_SIGNER_KEY", testKey)
+	tests := []struct {
+		name    string
+		format  string
+		data    string
+		wantKey string // key of the expected ValidationError
+		wantErr bool
+		check   func(t *testing.T, cfg *Config)
+	}{
+		{
+			name:   "yaml",
+			format: FormatYAML,
+			data: `
+network: mainnet
+signer:
+  private_key: ${TEST_SIGNER_KEY}
+http:
+  timeout: 5s
+  force_http2: true
+retry:
+  max_attempts: 3
+  initial_backoff: 200ms
+relays:
+  - name: beaverbuild
+    url: https://rpc.beaverbuild.org
+`,
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Network != "mainnet" || cfg.Signer.PrivateKey != testKey || !cfg.HTTP.ForceHTTP2 {
+					t.Fatalf("unexpected config %+v", cfg)
+				}
+				if time.Duration(cfg.HTTP.Timeout) != 5*time.Second || time.Duration(cfg.Retry.InitialBackoff) != 200*time.Millisecond {
+					t.Fatalf("unexpected durations %+v %+v", cfg.HTTP, cfg.Retry)
+				}
+				if len(cfg.Relays) != 1 || cfg.Relays[0].Name != "beaverbuild" {
+					t.Fatalf("unexpected relays %+v", cfg.Relays)
+				}
+			},
+		},
+		{
+			name:   "toml",
+			format: FormatTOML,
+			data: `
+relay = "https://relay.example.org"
+
+[rate_limit]
+requests_per_second = 2.5
+burst = 5
+`,
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Relay != "https://relay.example.org" || cfg.RateLimit.RequestsPerSecond != 2.5 || cfg.RateLimit.Burst != 5 {
+					t.Fatalf("unexpected config %+v", cfg)
+				}
+			},
+		},
+		{
+			name:   "env default",
+			format: FormatYAML,
+			data:   "network: ${TEST_UNSET_NETWORK:-sepolia}\n",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Network != "sepolia" {
+					t.Fatalf("network = %q, want sepolia", cfg.Network)
+				}
+			},
+		},
+		{name: "unset env", format: FormatYAML, data: "network: mainnet\nrelay: ${TEST_UNSET_RELAY}\n", wantErr: true},
+		{name: "unknown yaml key", format: FormatYAML, data: "network: mainnet\nretries: 3\n", wantErr: true},
+		{name: "unknown toml key", format: FormatTOML, data: "network = \"mainnet\"\nretries = 3\n", wantKey: "retries"},
+		{name: "invalid duration", format: FormatYAML, data: "network: mainnet\nhttp:\n  timeout: soon\n", wantErr: true},
+		{name: "unsupported format", format: "json", data: "{}", wantErr: true},
+		{name: "no network or relay", format: FormatYAML, data: "http:\n  timeout: 1s\n", wantKey: "network"},
+		{name: "unknown network", format: FormatYAML, data: "network: nowhere\n", wantKey: "network"},
+		{name: "invalid relay url", format: FormatYAML, data: "relay: ftp://relay\n", wantKey: "relay"},
+		{name: "invalid private key", format: FormatYAML, data: "network: mainnet\nsigner:\n  private_key: 0x1234\n", wantKey: "signer.private_key"},
+		{name: "negative timeout", format: FormatYAML, data: "network: mainnet\nhttp:\n  timeout: -1s\n", wantKey: "http.timeout"},
+		{name: "negative retries", format: FormatYAML, data: "network: mainnet\nretry:\n  max_attempts: -1\n", wantKey: "retry.max_attempts"},
+		{
+			name:    "backoff bounds",
+			format:  FormatYAML,
+			data:    "network: mainnet\nretry:\n  initial_backoff: 2s\n  max_backoff: 1s\n",
+			wantKey: "retry.max_backoff",
+		},
+		{
+			name:    "duplicate relay",
+			format:  FormatYAML,
+			data:    "network: mainnet\nrelays:\n  - name: a\n    url: https://a.org\n  - name: a\n    url: https://b.org\n",
+			wantKey: "relays[1].name",
+		},
+		{
+			name:    "relay signer",
+			format:  FormatYAML,
+			data:    "network: mainnet\nrelays:\n  - name: a\n    url: https://a.org\n    signer:\n      private_key: nope\n",
+			wantKey: "relays[0].signer.private_key",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Parse([]byte(tt.data), tt.format)
+			if tt.wantKey != "" {
+				var verr *ValidationError
+				if !errors.As(err, &verr) || verr.Key != tt.wantKey {
+					t.Fatalf("Parse() error = %v, want validation error for %s", err, tt.wantKey)
+				}
+				return
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		file    string
+		data    string
+		wantErr bool
+	}{
+		{"client.yaml", "network: mainnet\n", false},
+		{"client.yml", "network: mainnet\n", false},
+		{"client.toml", "network = \"mainnet\"\n", false},
+		{"client.json", `{"network":"mainnet"}`, true},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.file)
+		if err := os.WriteFile(path, []byte(tt.data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); (err != nil) != tt.wantErr {
+			t.Fatalf("Load(%s) error = %v, wantErr %v", tt.file, err, tt.wantErr)
+		}
+	}
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Fatal("Load() of a missing file succeeded")
+	}
+}
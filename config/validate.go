@@ -0,0 +1,146 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ValidationError points at the offending key, e.g. "retry.max_attempts".
+type ValidationError struct {
+	Key string
+	Msg string
+}
+
+func (err *ValidationError) Error() string {
+	return fmt.Sprintf("config: %s: %s", err.Key, err.Msg)
+}
+
+func invalid(key, format string, args ...interface{}) error {
+	return &ValidationError{Key: key, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (c *Config) Validate() error {
+	if c.Network == "" && c.Relay == "" {
+		return invalid("network", "either network or relay is required")
+	}
+	if c.Network != "" {
+		if _, err := common.LookupNetwork(c.Network); err != nil {
+			return invalid("network", "%v", err)
+		}
+	}
+	if c.Relay != "" {
+		if err := validateURL(c.Relay); err != nil {
+			return invalid("relay", "%v", err)
+		}
+	}
+	if err := c.Signer.validate("signer"); err != nil {
+		return err
+	}
+	if c.HTTP.Timeout < 0 {
+		return invalid("http.timeout", "must not be negative")
+	}
+	if c.HTTP.MaxResponseSize < 0 {
+		return invalid("http.max_response_size", "must not be negative")
+	}
+	if c.HTTP.KeepAlive < 0 {
+		return invalid("http.keep_alive", "must not be negative")
+	}
+	if c.HTTP.IdleConnTimeout < 0 {
+		return invalid("http.idle_conn_timeout", "must not be negative")
+	}
+	if c.HTTP.MaxIdleConnsPerHost < 0 {
+		return invalid("http.max_idle_conns_per_host", "must not be negative")
+	}
+	if c.Retry.MaxAttempts < 0 {
+		return invalid("retry.max_attempts", "must not be negative")
+	}
+	if c.Retry.InitialBackoff < 0 {
+		return invalid("retry.initial_backoff", "must not be negative")
+	}
+	if c.Retry.MaxBackoff < 0 {
+		return invalid("retry.max_backoff", "must not be negative")
+	}
+	if c.Retry.MaxBackoff > 0 && c.Retry.MaxBackoff < c.Retry.InitialBackoff {
+		return invalid("retry.max_backoff", "must not be less than retry.initial_backoff")
+	}
+	if c.RateLimit.RequestsPerSecond < 0 {
+		return invalid("rate_limit.requests_per_second", "must not be negative")
+	}
+	if c.RateLimit.Burst < 0 {
+		return invalid("rate_limit.burst", "must not be negative")
+	}
+	names := make(map[string]struct{}, len(c.Relays))
+	for i, r := range c.Relays {
+		key := fmt.Sprintf("relays[%d]", i)
+		if r.Name == "" {
+			return invalid(key+".name", "is required")
+		}
+		if _, ok := names[r.Name]; ok {
+			return invalid(key+".name", "duplicate relay %q", r.Name)
+		}
+		names[r.Name] = struct{}{}
+		if err := validateURL(r.URL); err != nil {
+			return invalid(key+".url", "%v", err)
+		}
+		if r.Signer != nil {
+			if err := r.Signer.validate(key + ".signer"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *SignerConfig) validate(key string) error {
+	if s.PrivateKey != "" && s.KeyFile != "" {
+		return invalid(key, "private_key and key_file are mutually exclusive")
+	}
+	if s.KeyFile != "" {
+		if _, err := os.Stat(s.KeyFile); err != nil {
+			return invalid(key+".key_file", "%v", err)
+		}
+		return nil
+	}
+	if s.PrivateKey != "" {
+		// never echo the key itself
+		if _, err := crypto.HexToECDSA(strings.TrimPrefix(s.PrivateKey, "0x")); err != nil {
+			return invalid(key+".private_key", "invalid private key")
+		}
+	}
+	return nil
+}
+
+// key returns the hex private key without 0x prefix, or "" to fall back to $SIGNER_PRIVATE_KEY.
+func (s *SignerConfig) key() (string, error) {
+	key := s.PrivateKey
+	if s.KeyFile != "" {
+		b, err := os.ReadFile(s.KeyFile)
+		if err != nil {
+			return "", err
+		}
+		key = strings.TrimSpace(string(b))
+		if _, err = crypto.HexToECDSA(strings.TrimPrefix(key, "0x")); err != nil {
+			return "", fmt.Errorf("%s: invalid private key", s.KeyFile)
+		}
+	}
+	return strings.TrimPrefix(key, "0x"), nil
+}
+
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("missing host in %q", raw)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"errors"
+	"math/big"
+
+	common2 "github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+)
+
+// BribeParams are the inputs of CalculateBribe.
+type BribeParams struct {
+	TargetGasPrice *big.Int // effective bundle gas price to reach, in wei per gas
+	GrossProfit    *big.Int // optional, value extracted by the bundle before any payment
+	BaseFee        *big.Int // optional, base fee of the target block, counted as a cost
+}
+
+// BribeQuote is the coinbase payment needed for a simulated bundle to reach the target gas price.
+type BribeQuote struct {
+	TotalGasUsed *big.Int
+	// CoinbasePayment is what the simulated bundle already pays, priority fees included.
+	CoinbasePayment *big.Int
+	GasPrice        *big.Int
+	// RequiredTransfer is the extra coinbase transfer to add, zero when the target is met.
+	RequiredTransfer *big.Int
+	// TotalPayment is CoinbasePayment plus RequiredTransfer.
+	TotalPayment *big.Int
+	BaseFeeCost  *big.Int
+	// NetProfit is GrossProfit minus TotalPayment and BaseFeeCost, nil without GrossProfit.
+	NetProfit *big.Int
+}
+
+// Profitable reports whether the bundle still makes money after the bribe.
+func (q *BribeQuote) Profitable() bool {
+	return q.NetProfit != nil && q.NetProfit.Sign() > 0
+}
+
+// CalculateBribe computes the coinbase transfer needed for res to pay TargetGasPrice per gas.
+// The effective gas price of a bundle is its coinbase diff divided by the gas it uses.
+func CalculateBribe(res *common2.CallBundleResponse, params BribeParams) (*BribeQuote, error) {
+	if res == nil {
+		return nil, errors.New("bribe: nil simulation result")
+	}
+	if params.TargetGasPrice == nil || params.TargetGasPrice.Sign() < 0 {
+		return nil, errors.New("bribe: invalid target gas price")
+	}
+	if res.TotalGasUsed == 0 {
+		return nil, errors.New("bribe: bundle used no gas")
+	}
+	gasUsed := new(big.Int).SetUint64(res.TotalGasUsed)
+	coinbaseDiff := new(big.Int)
+	if res.CoinbaseDiff != nil {
+		coinbaseDiff.Set(res.CoinbaseDiff)
+	}
+
+	quote := &BribeQuote{
+		TotalGasUsed:    gasUsed,
+		CoinbasePayment: coinbaseDiff,
+		GasPrice:        new(big.Int).Div(coinbaseDiff, gasUsed),
+		BaseFeeCost:     new(big.Int),
+	}
+	target := new(big.Int).Mul(params.TargetGasPrice, gasUsed)
+	quote.RequiredTransfer = new(big.Int).Sub(target, coinbaseDiff)
+	if quote.RequiredTransfer.Sign() < 0 {
+		quote.RequiredTransfer.SetInt64(0)
+	}
+	quote.TotalPayment = new(big.Int).Add(coinbaseDiff, quote.RequiredTransfer)
+	if params.BaseFee != nil {
+		quote.BaseFeeCost.Mul(params.BaseFee, gasUsed)
+	}
+	if params.GrossProfit != nil {
+		quote.NetProfit = new(big.Int).Sub(params.GrossProfit, quote.TotalPayment)
+		quote.NetProfit.Sub(quote.NetProfit, quote.BaseFeeCost)
+	}
+	return quote, nil
+}
@@ -0,0 +1,109 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	common2 "github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+)
+
+// simulation decodes a relay eth_callBundle result with the given totals.
+func simulation(t *testing.T, coinbaseDiff string, totalGasUsed int64) *common2.CallBundleResponse {
+	t.Helper()
+	var res common2.CallBundleResponse
+	payload := fmt.Sprintf(`{"coinbaseDiff":%q,"ethSentToCoinbase":"0","bundleGasPrice":"0","totalGasUsed":%d}`, coinbaseDiff, totalGasUsed)
+	if err := json.Unmarshal([]byte(payload), &res); err != nil {
+		t.Fatal(err)
+	}
+	return &res
+}
+
+func bigOrNil(n *int64) *big.Int {
+	if n == nil {
+		return nil
+	}
+	return big.NewInt(*n)
+}
+
+func TestCalculateBribe(t *testing.T) {
+	i64 := func(n int64) *int64 { return &n }
+	tests := []struct {
+		name         string
+		coinbaseDiff string
+		gasUsed      int64
+		target       *int64
+		grossProfit  *int64
+		baseFee      *int64
+		wantGasPrice int64
+		wantRequired int64
+		wantTotal    int64
+		wantBaseFee  int64
+		wantNet      *int64
+		wantErr      bool
+	}{
+		{name: "below target", coinbaseDiff: "1000000", gasUsed: 100000, target: i64(25),
+			wantGasPrice: 10, wantRequired: 1500000, wantTotal: 2500000},
+		{name: "target met clamps transfer at zero", coinbaseDiff: "3000000", gasUsed: 100000, target: i64(20),
+			wantGasPrice: 30, wantRequired: 0, wantTotal: 3000000},
+		{name: "gas price rounds down", coinbaseDiff: "100", gasUsed: 30, target: i64(0),
+			wantGasPrice: 3, wantRequired: 0, wantTotal: 100},
+		{name: "base fee cost", coinbaseDiff: "1000000", gasUsed: 100000, target: i64(10), baseFee: i64(7),
+			wantGasPrice: 10, wantRequired: 0, wantTotal: 1000000, wantBaseFee: 700000},
+		{name: "net profit", coinbaseDiff: "1000000", gasUsed: 100000, target: i64(25), grossProfit: i64(5000000), baseFee: i64(7),
+			wantGasPrice: 10, wantRequired: 1500000, wantTotal: 2500000, wantBaseFee: 700000, wantNet: i64(1800000)},
+		{name: "loss", coinbaseDiff: "0", gasUsed: 100000, target: i64(25), grossProfit: i64(1000000),
+			wantGasPrice: 0, wantRequired: 2500000, wantTotal: 2500000, wantNet: i64(-1500000)},
+		{name: "hex coinbase diff", coinbaseDiff: "0xf4240", gasUsed: 100000, target: i64(10),
+			wantGasPrice: 10, wantRequired: 0, wantTotal: 1000000},
+		{name: "missing target", coinbaseDiff: "0", gasUsed: 100000, wantErr: true},
+		{name: "negative target", coinbaseDiff: "0", gasUsed: 100000, target: i64(-1), wantErr: true},
+		{name: "no gas used", coinbaseDiff: "0", gasUsed: 0, target: i64(1), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := CalculateBribe(simulation(t, tt.coinbaseDiff, tt.gasUsed), BribeParams{
+				TargetGasPrice: bigOrNil(tt.target),
+				GrossProfit:    bigOrNil(tt.grossProfit),
+				BaseFee:        bigOrNil(tt.baseFee),
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CalculateBribe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if quote.TotalGasUsed.Int64() != tt.gasUsed {
+				t.Fatalf("TotalGasUsed = %v, want %d", quote.TotalGasUsed, tt.gasUsed)
+			}
+			if quote.GasPrice.Int64() != tt.wantGasPrice {
+				t.Fatalf("GasPrice = %v, want %d", quote.GasPrice, tt.wantGasPrice)
+			}
+			if quote.RequiredTransfer.Int64() != tt.wantRequired {
+				t.Fatalf("RequiredTransfer = %v, want %d", quote.RequiredTransfer, tt.wantRequired)
+			}
+			if quote.TotalPayment.Int64() != tt.wantTotal {
+				t.Fatalf("TotalPayment = %v, want %d", quote.TotalPayment, tt.wantTotal)
+			}
+			if quote.BaseFeeCost.Int64() != tt.wantBaseFee {
+				t.Fatalf("BaseFeeCost = %v, want %d", quote.BaseFeeCost, tt.wantBaseFee)
+			}
+			if tt.wantNet == nil {
+				if quote.NetProfit != nil || quote.Profitable() {
+					t.Fatalf("NetProfit = %v, want nil", quote.NetProfit)
+				}
+				return
+			}
+			if quote.NetProfit == nil || quote.NetProfit.Int64() != *tt.wantNet {
+				t.Fatalf("NetProfit = %v, want %d", quote.NetProfit, *tt.wantNet)
+			}
+			if quote.Profitable() != (*tt.wantNet > 0) {
+				t.Fatalf("Profitable() = %v, want %v", quote.Profitable(), *tt.wantNet > 0)
+			}
+		})
+	}
+	if _, err := CalculateBribe(nil, BribeParams{TargetGasPrice: big.NewInt(1)}); err == nil {
+		t.Fatal("CalculateBribe(nil) succeeded, want error")
+	}
+}
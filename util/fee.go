@@ -0,0 +1,240 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Fees are the EIP-1559 caps applied to a transaction.
+type Fees struct {
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+}
+
+// FeeStrategy prices a transaction with the given gas limit.
+type FeeStrategy interface {
+	Fees(ctx context.Context, gasLimit uint64) (*Fees, error)
+}
+
+// GasPriceSuggester is the subset of ethclient.Client used by SuggestedFee.
+type GasPriceSuggester interface {
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+type fixedFee struct {
+	fees Fees
+}
+
+// FixedFee always returns the given caps.
+func FixedFee(gasTipCap, gasFeeCap *big.Int) FeeStrategy {
+	return &fixedFee{fees: Fees{GasTipCap: gasTipCap, GasFeeCap: gasFeeCap}}
+}
+
+func (f *fixedFee) Fees(_ context.Context, _ uint64) (*Fees, error) {
+	if f.fees.GasTipCap == nil || f.fees.GasFeeCap == nil {
+		return nil, errors.New("fixed fee: caps not set")
+	}
+	return &Fees{
+		GasTipCap: new(big.Int).Set(f.fees.GasTipCap),
+		GasFeeCap: new(big.Int).Set(f.fees.GasFeeCap),
+	}, nil
+}
+
+type suggestedFee struct {
+	backend GasPriceSuggester
+}
+
+// SuggestedFee uses the node's eth_maxPriorityFeePerGas and eth_gasPrice suggestions.
+func SuggestedFee(backend GasPriceSuggester) FeeStrategy {
+	return &suggestedFee{backend: backend}
+}
+
+func (f *suggestedFee) Fees(ctx context.Context, _ uint64) (*Fees, error) {
+	tip, err := f.backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, err := f.backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// eth_gasPrice already includes the tip, doubling it leaves room for base fee growth
+	feeCap := new(big.Int).Mul(gasPrice, big.NewInt(2))
+	if feeCap.Cmp(tip) < 0 {
+		feeCap.Set(tip)
+	}
+	return &Fees{GasTipCap: tip, GasFeeCap: feeCap}, nil
+}
+
+const (
+	baseFeeChangeDenominator = 8 // EIP-1559 BASE_FEE_MAX_CHANGE_DENOMINATOR
+	elasticityMultiplier     = 2 // EIP-1559 ELASTICITY_MULTIPLIER
+)
+
+// HeaderReader is the subset of ethclient.Client used to read the chain head.
+type HeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// FeeHistoryReader is the subset of ethclient.Client used by PercentileFee.
+type FeeHistoryReader interface {
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error)
+}
+
+// NextBaseFee computes the base fee of the block following parent as specified by EIP-1559.
+func NextBaseFee(parent *types.Header) *big.Int {
+	if parent.BaseFee == nil {
+		return new(big.Int)
+	}
+	parentGasTarget := parent.GasLimit / elasticityMultiplier
+	baseFee := new(big.Int).Set(parent.BaseFee)
+	if parentGasTarget == 0 || parent.GasUsed == parentGasTarget {
+		return baseFee
+	}
+	if parent.GasUsed > parentGasTarget {
+		delta := new(big.Int).SetUint64(parent.GasUsed - parentGasTarget)
+		delta.Mul(delta, parent.BaseFee)
+		delta.Div(delta, new(big.Int).SetUint64(parentGasTarget))
+		delta.Div(delta, big.NewInt(baseFeeChangeDenominator))
+		if delta.Sign() == 0 {
+			delta.SetInt64(1)
+		}
+		return baseFee.Add(baseFee, delta)
+	}
+	delta := new(big.Int).SetUint64(parentGasTarget - parent.GasUsed)
+	delta.Mul(delta, parent.BaseFee)
+	delta.Div(delta, new(big.Int).SetUint64(parentGasTarget))
+	delta.Div(delta, big.NewInt(baseFeeChangeDenominator))
+	baseFee.Sub(baseFee, delta)
+	if baseFee.Sign() < 0 {
+		baseFee.SetInt64(0)
+	}
+	return baseFee
+}
+
+// MaxBaseFee bounds the base fee n blocks after a block with baseFee, assuming every
+// block in between is full (+12.5% each).
+func MaxBaseFee(baseFee *big.Int, n uint64) *big.Int {
+	fee := new(big.Int).Set(baseFee)
+	for i := uint64(0); i < n; i++ {
+		fee.Mul(fee, big.NewInt(baseFeeChangeDenominator+1))
+		fee.Div(fee, big.NewInt(baseFeeChangeDenominator))
+	}
+	return fee
+}
+
+type baseFeeProjection struct {
+	backend     HeaderReader
+	tip         *big.Int
+	blocksAhead uint64
+}
+
+// BaseFeeProjection prices a tx for the block blocksAhead after the current head (1 is the
+// next block). The fee cap covers the worst-case base fee of that block plus tip.
+func BaseFeeProjection(backend HeaderReader, tip *big.Int, blocksAhead uint64) FeeStrategy {
+	if blocksAhead == 0 {
+		blocksAhead = 1
+	}
+	return &baseFeeProjection{backend: backend, tip: tip, blocksAhead: blocksAhead}
+}
+
+func (f *baseFeeProjection) Fees(ctx context.Context, _ uint64) (*Fees, error) {
+	parent, err := f.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	baseFee := MaxBaseFee(NextBaseFee(parent), f.blocksAhead-1)
+	tip := new(big.Int)
+	if f.tip != nil {
+		tip.Set(f.tip)
+	}
+	return &Fees{GasTipCap: tip, GasFeeCap: baseFee.Add(baseFee, tip)}, nil
+}
+
+type percentileFee struct {
+	backend     FeeHistoryReader
+	percentile  float64
+	blockCount  uint64
+	blocksAhead uint64
+}
+
+// PercentileFee sets the tip to the average of the given priority-fee percentile over
+// the last blockCount blocks and projects the base fee blocksAhead blocks forward.
+func PercentileFee(backend FeeHistoryReader, percentile float64, blockCount, blocksAhead uint64) FeeStrategy {
+	if blockCount == 0 {
+		blockCount = 10
+	}
+	if blocksAhead == 0 {
+		blocksAhead = 1
+	}
+	return &percentileFee{backend: backend, percentile: percentile, blockCount: blockCount, blocksAhead: blocksAhead}
+}
+
+func (f *percentileFee) Fees(ctx context.Context, _ uint64) (*Fees, error) {
+	if f.percentile < 0 || f.percentile > 100 {
+		return nil, fmt.Errorf("percentile fee: percentile %v out of range 0-100", f.percentile)
+	}
+	history, err := f.backend.FeeHistory(ctx, f.blockCount, nil, []float64{f.percentile})
+	if err != nil {
+		return nil, err
+	}
+	if len(history.BaseFee) == 0 {
+		return nil, errors.New("percentile fee: empty fee history")
+	}
+	tip := new(big.Int)
+	var samples int64
+	for _, rewards := range history.Reward {
+		if len(rewards) == 0 || rewards[0] == nil {
+			continue
+		}
+		tip.Add(tip, rewards[0])
+		samples++
+	}
+	if samples > 0 {
+		tip.Div(tip, big.NewInt(samples))
+	}
+	// the last base fee entry already belongs to the next block
+	baseFee := MaxBaseFee(history.BaseFee[len(history.BaseFee)-1], f.blocksAhead-1)
+	return &Fees{GasTipCap: tip, GasFeeCap: baseFee.Add(baseFee, tip)}, nil
+}
+
+type coinbasePayment struct {
+	backend     HeaderReader
+	payment     *big.Int
+	blocksAhead uint64
+}
+
+// CoinbasePayment spreads a fixed coinbase payment over the gas limit through the priority
+// fee. The builder receives payment only if the tx uses its whole gas limit, so pair it
+// with an exact gas limit rather than a padded estimate.
+func CoinbasePayment(backend HeaderReader, payment *big.Int, blocksAhead uint64) FeeStrategy {
+	if blocksAhead == 0 {
+		blocksAhead = 1
+	}
+	return &coinbasePayment{backend: backend, payment: payment, blocksAhead: blocksAhead}
+}
+
+func (f *coinbasePayment) Fees(ctx context.Context, gasLimit uint64) (*Fees, error) {
+	if gasLimit == 0 {
+		return nil, errors.New("coinbase payment: gas limit required")
+	}
+	if f.payment == nil || f.payment.Sign() < 0 {
+		return nil, errors.New("coinbase payment: invalid payment")
+	}
+	parent, err := f.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	gas := new(big.Int).SetUint64(gasLimit)
+	// round up so the payment is not undershot by integer division
+	tip := new(big.Int).Add(f.payment, new(big.Int).Sub(gas, big.NewInt(1)))
+	tip.Div(tip, gas)
+	baseFee := MaxBaseFee(NextBaseFee(parent), f.blocksAhead-1)
+	return &Fees{GasTipCap: tip, GasFeeCap: baseFee.Add(baseFee, tip)}, nil
+}
@@ -0,0 +1,117 @@
+package util
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type fakeHeaderReader struct {
+	header *types.Header
+}
+
+func (r fakeHeaderReader) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return r.header, nil
+}
+
+type fakeFeeHistory struct {
+	history *ethereum.FeeHistory
+}
+
+func (r fakeFeeHistory) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	return r.history, nil
+}
+
+func TestNextBaseFee(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseFee int64
+		gasUsed uint64
+		want    int64
+	}{
+		{"at target", 1000, 15_000_000, 1000},
+		{"full block", 1000, 30_000_000, 1125},
+		{"empty block", 1000, 0, 875},
+		{"half above target", 1000, 22_500_000, 1062},
+		{"minimal increase", 1, 15_000_001, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := &types.Header{BaseFee: big.NewInt(tt.baseFee), GasLimit: 30_000_000, GasUsed: tt.gasUsed}
+			if got := NextBaseFee(parent); got.Int64() != tt.want {
+				t.Fatalf("NextBaseFee() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := NextBaseFee(&types.Header{}); got.Sign() != 0 {
+		t.Fatalf("pre-London NextBaseFee() = %v, want 0", got)
+	}
+}
+
+func TestMaxBaseFee(t *testing.T) {
+	tests := []struct {
+		baseFee int64
+		n       uint64
+		want    int64
+	}{
+		{800, 0, 800},
+		{800, 1, 900},
+		{800, 2, 1012},
+		{100_000_000_000, 3, 142_382_812_500},
+	}
+	for _, tt := range tests {
+		if got := MaxBaseFee(big.NewInt(tt.baseFee), tt.n); got.Int64() != tt.want {
+			t.Fatalf("MaxBaseFee(%d, %d) = %v, want %v", tt.baseFee, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestFeeStrategies(t *testing.T) {
+	ctx := context.Background()
+	head := fakeHeaderReader{&types.Header{BaseFee: big.NewInt(1000), GasLimit: 30_000_000, GasUsed: 30_000_000}}
+	tests := []struct {
+		name     string
+		strategy FeeStrategy
+		gasLimit uint64
+		wantTip  int64
+		wantCap  int64
+		wantErr  bool
+	}{
+		{"fixed", FixedFee(big.NewInt(2), big.NewInt(50)), 0, 2, 50, false},
+		{"fixed without caps", FixedFee(nil, nil), 0, 0, 0, true},
+		{"projection next block", BaseFeeProjection(head, big.NewInt(10), 1), 0, 10, 1135, false},
+		{"projection two blocks", BaseFeeProjection(head, big.NewInt(10), 2), 0, 10, 1275, false},
+		{"projection without tip", BaseFeeProjection(head, nil, 0), 0, 0, 1125, false},
+		{"coinbase payment", CoinbasePayment(head, big.NewInt(21_001), 1), 21_000, 2, 1127, false},
+		{"coinbase payment exact", CoinbasePayment(head, big.NewInt(42_000), 1), 21_000, 2, 1127, false},
+		{"coinbase payment without gas limit", CoinbasePayment(head, big.NewInt(1), 1), 0, 0, 0, true},
+		{"coinbase payment negative", CoinbasePayment(head, big.NewInt(-1), 1), 21_000, 0, 0, true},
+		{
+			"percentile",
+			PercentileFee(fakeFeeHistory{&ethereum.FeeHistory{
+				BaseFee: []*big.Int{big.NewInt(900), big.NewInt(1000)},
+				Reward:  [][]*big.Int{{big.NewInt(4)}, {big.NewInt(6)}, {}},
+			}}, 50, 2, 1),
+			0, 5, 1005, false,
+		},
+		{"percentile out of range", PercentileFee(fakeFeeHistory{}, 101, 0, 0), 0, 0, 0, true},
+		{"percentile empty history", PercentileFee(fakeFeeHistory{&ethereum.FeeHistory{}}, 50, 0, 0), 0, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fees, err := tt.strategy.Fees(ctx, tt.gasLimit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fees() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if fees.GasTipCap.Int64() != tt.wantTip || fees.GasFeeCap.Int64() != tt.wantCap {
+				t.Fatalf("Fees() = tip %v cap %v, want tip %d cap %d", fees.GasTipCap, fees.GasFeeCap, tt.wantTip, tt.wantCap)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package util
+
+import (
+	"fmt"
+	"math/big"
+
+	common2 "github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DecodedTx is the readable form of a raw bundle tx, joined with its simulation result.
+type DecodedTx struct {
+	Index     int             `json:"index"`
+	Hash      common.Hash     `json:"hash"`
+	Type      uint8           `json:"type"`
+	ChainID   *big.Int        `json:"chainId,omitempty"`
+	From      common.Address  `json:"from"`
+	To        *common.Address `json:"to"` // nil for contract creation
+	Nonce     uint64          `json:"nonce"`
+	Value     *big.Int        `json:"value"`
+	Gas       uint64          `json:"gas"`
+	GasPrice  *big.Int        `json:"gasPrice,omitempty"`  // legacy and access list txs
+	GasFeeCap *big.Int        `json:"gasFeeCap,omitempty"` // dynamic fee txs
+	GasTipCap *big.Int        `json:"gasTipCap,omitempty"` // dynamic fee txs
+	// AccessListSize is the number of addresses and storage keys of the access list.
+	AccessListSize int           `json:"accessListSize,omitempty"`
+	Selector       hexutil.Bytes `json:"selector,omitempty"` // first 4 bytes of the calldata
+	DataSize       int           `json:"dataSize"`
+	// Simulation is the eth_callBundle result of the tx, nil when not simulated.
+	Simulation *common2.TxSimulationResponse `json:"simulation,omitempty"`
+}
+
+func (d *DecodedTx) TypeName() string {
+	switch d.Type {
+	case types.LegacyTxType:
+		return "legacy"
+	case types.AccessListTxType:
+		return "access_list"
+	case types.DynamicFeeTxType:
+		return "dynamic_fee"
+	default:
+		return fmt.Sprintf("type_%d", d.Type)
+	}
+}
+
+func (d *DecodedTx) String() string {
+	to := "create"
+	if d.To != nil {
+		to = d.To.Hex()
+	}
+	return fmt.Sprintf("#%d %s %s %s -> %s nonce=%d value=%s gas=%d", d.Index, d.Hash.Hex(), d.TypeName(), d.From.Hex(), to, d.Nonce, d.Value, d.Gas)
+}
+
+// DecodeTx decodes a hex encoded signed tx and recovers its sender.
+func DecodeTx(rawTx string) (*DecodedTx, error) {
+	b, err := hexutil.Decode(rawTx)
+	if err != nil {
+		return nil, err
+	}
+	if err = common2.CheckTxType(b); err != nil {
+		return nil, err
+	}
+	tx := new(types.Transaction)
+	if err = tx.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover sender: %w", err)
+	}
+	d := &DecodedTx{
+		Hash:     tx.Hash(),
+		Type:     tx.Type(),
+		From:     from,
+		To:       tx.To(),
+		Nonce:    tx.Nonce(),
+		Value:    tx.Value(),
+		Gas:      tx.Gas(),
+		DataSize: len(tx.Data()),
+	}
+	if tx.Protected() {
+		d.ChainID = tx.ChainId()
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		d.GasFeeCap, d.GasTipCap = tx.GasFeeCap(), tx.GasTipCap()
+	} else {
+		d.GasPrice = tx.GasPrice()
+	}
+	for _, tuple := range tx.AccessList() {
+		d.AccessListSize += 1 + len(tuple.StorageKeys)
+	}
+	if len(tx.Data()) >= 4 {
+		d.Selector = tx.Data()[:4]
+	}
+	return d, nil
+}
+
+// DecodeTxs decodes the txs of a bundle, e.g. SendBundleArgs.Txs or CallBundleArgs.Txs.
+func DecodeTxs(rawTxs []string) ([]*DecodedTx, error) {
+	decoded := make([]*DecodedTx, 0, len(rawTxs))
+	for i, rawTx := range rawTxs {
+		d, err := DecodeTx(rawTx)
+		if err != nil {
+			return nil, fmt.Errorf("tx %d: %w", i, err)
+		}
+		d.Index = i
+		decoded = append(decoded, d)
+	}
+	return decoded, nil
+}
+
+// InspectBundle decodes rawTxs and attaches the simulation result of each tx from res,
+// matched by tx hash. res may be nil.
+func InspectBundle(rawTxs []string, res *common2.CallBundleResponse) ([]*DecodedTx, error) {
+	decoded, err := DecodeTxs(rawTxs)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return decoded, nil
+	}
+	byHash := make(map[common.Hash]*common2.TxSimulationResponse, len(res.Results))
+	for i := range res.Results {
+		byHash[res.Results[i].TxHash] = &res.Results[i]
+	}
+	for _, d := range decoded {
+		d.Simulation = byHash[d.Hash]
+	}
+	return decoded, nil
+}
@@ -0,0 +1,200 @@
+package util
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	common2 "github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func encodeTx(t *testing.T, tx *types.Transaction) string {
+	t.Helper()
+	b, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hexutil.Encode(b)
+}
+
+func TestDecodeTx(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0x73625f59CAdc5009Cb458B751b3E7b6b48C06f2C")
+	transfer := hexutil.MustDecode("0xa9059cbb000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000003e8")
+	sign := func(tx types.TxData, signer types.Signer) string {
+		return encodeTx(t, types.MustSignNewTx(key, signer, tx))
+	}
+	tests := []struct {
+		name         string
+		rawTx        string
+		wantType     uint8
+		wantTypeName string
+		wantChainID  *big.Int
+		wantTo       *common.Address
+		wantGasPrice bool
+		wantSelector string
+		wantDataSize int
+		wantErr      string
+	}{
+		{
+			name:         "legacy unprotected",
+			rawTx:        sign(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(10), Gas: 21000, To: &to, Value: big.NewInt(1)}, types.HomesteadSigner{}),
+			wantType:     types.LegacyTxType,
+			wantTypeName: "legacy",
+			wantTo:       &to,
+			wantGasPrice: true,
+		},
+		{
+			name:         "legacy eip-155",
+			rawTx:        sign(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(10), Gas: 60000, To: &to, Data: transfer}, types.NewEIP155Signer(big.NewInt(1))),
+			wantType:     types.LegacyTxType,
+			wantTypeName: "legacy",
+			wantChainID:  big.NewInt(1),
+			wantTo:       &to,
+			wantGasPrice: true,
+			wantSelector: "0xa9059cbb",
+			wantDataSize: len(transfer),
+		},
+		{
+			name: "access list",
+			rawTx: sign(&types.AccessListTx{ChainID: big.NewInt(5), Nonce: 2, GasPrice: big.NewInt(10), Gas: 60000, To: &to, Data: transfer,
+				AccessList: types.AccessList{{Address: to, StorageKeys: []common.Hash{{}}}}}, types.NewEIP2930Signer(big.NewInt(5))),
+			wantType:     types.AccessListTxType,
+			wantTypeName: "access_list",
+			wantChainID:  big.NewInt(5),
+			wantTo:       &to,
+			wantGasPrice: true,
+			wantSelector: "0xa9059cbb",
+			wantDataSize: len(transfer),
+		},
+		{
+			name:         "dynamic fee",
+			rawTx:        sign(&types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 3, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(100), Gas: 21000, To: &to}, types.NewLondonSigner(big.NewInt(1))),
+			wantType:     types.DynamicFeeTxType,
+			wantTypeName: "dynamic_fee",
+			wantChainID:  big.NewInt(1),
+			wantTo:       &to,
+		},
+		{
+			name:         "contract creation with short data",
+			rawTx:        sign(&types.DynamicFeeTx{ChainID: big.NewInt(1), GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(100), Gas: 100000, Data: []byte{0x60, 0x80, 0x60}}, types.NewLondonSigner(big.NewInt(1))),
+			wantType:     types.DynamicFeeTxType,
+			wantTypeName: "dynamic_fee",
+			wantChainID:  big.NewInt(1),
+			wantDataSize: 3,
+		},
+		{name: "not hex", rawTx: "02f8", wantErr: "hex string without 0x prefix"},
+		{name: "not a tx", rawTx: "0x0102", wantErr: "rlp"},
+		{
+			name:    "unsigned",
+			rawTx:   encodeTx(t, types.NewTx(&types.LegacyTx{GasPrice: big.NewInt(10), Gas: 21000, To: &to})),
+			wantErr: "failed to recover sender",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := DecodeTx(tt.rawTx)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("DecodeTx() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.From != from {
+				t.Fatalf("From = %s, want %s", d.From.Hex(), from.Hex())
+			}
+			if d.Type != tt.wantType || d.TypeName() != tt.wantTypeName {
+				t.Fatalf("type = %d %s, want %d %s", d.Type, d.TypeName(), tt.wantType, tt.wantTypeName)
+			}
+			if (d.ChainID == nil) != (tt.wantChainID == nil) || (d.ChainID != nil && d.ChainID.Cmp(tt.wantChainID) != 0) {
+				t.Fatalf("ChainID = %v, want %v", d.ChainID, tt.wantChainID)
+			}
+			if (d.To == nil) != (tt.wantTo == nil) || (d.To != nil && *d.To != *tt.wantTo) {
+				t.Fatalf("To = %v, want %v", d.To, tt.wantTo)
+			}
+			if tt.wantGasPrice != (d.GasPrice != nil) || tt.wantGasPrice == (d.GasFeeCap != nil) || tt.wantGasPrice == (d.GasTipCap != nil) {
+				t.Fatalf("GasPrice = %v, GasFeeCap = %v, GasTipCap = %v", d.GasPrice, d.GasFeeCap, d.GasTipCap)
+			}
+			if got := d.Selector.String(); (len(d.Selector) == 0 && tt.wantSelector != "") || (len(d.Selector) > 0 && got != tt.wantSelector) {
+				t.Fatalf("Selector = %s, want %q", got, tt.wantSelector)
+			}
+			if d.DataSize != tt.wantDataSize {
+				t.Fatalf("DataSize = %d, want %d", d.DataSize, tt.wantDataSize)
+			}
+			tx := new(types.Transaction)
+			if err := tx.UnmarshalBinary(hexutil.MustDecode(tt.rawTx)); err != nil || d.Hash != tx.Hash() {
+				t.Fatalf("Hash = %s, want %s", d.Hash.Hex(), tx.Hash().Hex())
+			}
+		})
+	}
+}
+
+func TestInspectBundle(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x01")
+	signer := types.NewLondonSigner(big.NewInt(1))
+	var rawTxs []string
+	var hashes []common.Hash
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		tx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: nonce, GasTipCap: big.NewInt(2), GasFeeCap: big.NewInt(100), Gas: 21000, To: &to})
+		rawTxs = append(rawTxs, encodeTx(t, tx))
+		hashes = append(hashes, tx.Hash())
+	}
+	// results out of order, one tx missing and one result for a tx outside the bundle
+	res := &common2.CallBundleResponse{Results: []common2.TxSimulationResponse{
+		{TxHash: hashes[2], GasUsed: 21002},
+		{TxHash: common.HexToHash("0xdead"), GasUsed: 1},
+		{TxHash: hashes[0], GasUsed: 21000},
+	}}
+
+	decoded, err := InspectBundle(rawTxs, res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 3 {
+		t.Fatalf("decoded %d txs, want 3", len(decoded))
+	}
+	wantGas := []uint64{21000, 0, 21002}
+	for i, d := range decoded {
+		if d.Index != i || d.Hash != hashes[i] || d.Nonce != uint64(i) {
+			t.Fatalf("tx %d = %s", i, d)
+		}
+		if wantGas[i] == 0 {
+			if d.Simulation != nil {
+				t.Fatalf("tx %d simulation = %+v, want nil", i, d.Simulation)
+			}
+			continue
+		}
+		if d.Simulation == nil || d.Simulation.TxHash != hashes[i] || d.Simulation.GasUsed != wantGas[i] {
+			t.Fatalf("tx %d simulation = %+v", i, d.Simulation)
+		}
+	}
+
+	decoded, err = InspectBundle(rawTxs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range decoded {
+		if d.Simulation != nil {
+			t.Fatalf("tx %d simulation = %+v without a result", d.Index, d.Simulation)
+		}
+	}
+
+	if _, err := InspectBundle([]string{rawTxs[0], "0x0102"}, res); err == nil || !strings.HasPrefix(err.Error(), "tx 1:") {
+		t.Fatalf("InspectBundle() error = %v, want the index of the bad tx", err)
+	}
+}
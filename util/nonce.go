@@ -0,0 +1,143 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	common2 "github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// NonceSource is the subset of ethclient.Client used to read account nonces.
+type NonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager hands out sequential nonces per sender so that several transactions
+// built for the same bundle do not collide.
+type NonceManager interface {
+	// Reserve returns the next free nonce for addr.
+	Reserve(ctx context.Context, addr common.Address) (uint64, error)
+	// ReserveN returns n consecutive nonces for addr, as needed by a bundle.
+	ReserveN(ctx context.Context, addr common.Address, n int) ([]uint64, error)
+	// Release returns nonces that were not used, e.g. after a failed submission.
+	Release(addr common.Address, nonces ...uint64)
+	// Resync discards local state for addr and reloads the pending nonce from chain.
+	Resync(ctx context.Context, addr common.Address) error
+}
+
+type accountNonce struct {
+	next     uint64
+	released []uint64 // sorted, all below next
+}
+
+type nonceManager struct {
+	logger   *zap.Logger
+	source   NonceSource
+	mu       sync.Mutex // protects accounts
+	accounts map[common.Address]*accountNonce
+}
+
+func NewNonceManager(source NonceSource) NonceManager {
+	return &nonceManager{
+		logger:   common2.NewLogger(),
+		source:   source,
+		accounts: make(map[common.Address]*accountNonce),
+	}
+}
+
+func (nm *nonceManager) Reserve(ctx context.Context, addr common.Address) (uint64, error) {
+	nonces, err := nm.ReserveN(ctx, addr, 1)
+	if err != nil {
+		return 0, err
+	}
+	return nonces[0], nil
+}
+
+func (nm *nonceManager) ReserveN(ctx context.Context, addr common.Address, n int) ([]uint64, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("nonce manager: cannot reserve %d nonces", n)
+	}
+	// read the chain outside the lock, nonces consumed externally move the account forward
+	pending, err := nm.source.PendingNonceAt(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	acc := nm.sync(addr, pending)
+	nonces := make([]uint64, 0, n)
+	if n == 1 && len(acc.released) > 0 {
+		// reuse the lowest gap first so no nonce is left unmined
+		nonces = append(nonces, acc.released[0])
+		acc.released = acc.released[1:]
+		return nonces, nil
+	}
+	// bundles need consecutive nonces, which only the tail guarantees
+	for i := 0; i < n; i++ {
+		nonces = append(nonces, acc.next)
+		acc.next++
+	}
+	return nonces, nil
+}
+
+func (nm *nonceManager) Release(addr common.Address, nonces ...uint64) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	acc, ok := nm.accounts[addr]
+	if !ok {
+		return
+	}
+	for _, nonce := range nonces {
+		if nonce >= acc.next {
+			continue
+		}
+		i := sort.Search(len(acc.released), func(i int) bool { return acc.released[i] >= nonce })
+		if i < len(acc.released) && acc.released[i] == nonce {
+			continue
+		}
+		acc.released = append(acc.released, 0)
+		copy(acc.released[i+1:], acc.released[i:])
+		acc.released[i] = nonce
+	}
+	// shrink the tail so the next reservation reuses released nonces in order
+	for len(acc.released) > 0 && acc.released[len(acc.released)-1] == acc.next-1 {
+		acc.released = acc.released[:len(acc.released)-1]
+		acc.next--
+	}
+}
+
+func (nm *nonceManager) Resync(ctx context.Context, addr common.Address) error {
+	pending, err := nm.source.PendingNonceAt(ctx, addr)
+	if err != nil {
+		return err
+	}
+	nm.mu.Lock()
+	nm.accounts[addr] = &accountNonce{next: pending}
+	nm.mu.Unlock()
+	return nil
+}
+
+// sync moves the account forward to the chain's pending nonce when it is ahead. Must hold nm.mu.
+func (nm *nonceManager) sync(addr common.Address, pending uint64) *accountNonce {
+	acc, ok := nm.accounts[addr]
+	if !ok {
+		acc = &accountNonce{next: pending}
+		nm.accounts[addr] = acc
+		return acc
+	}
+	if pending > acc.next {
+		nm.logger.Warn("nonce consumed externally, resyncing",
+			zap.String("address", addr.Hex()), zap.Uint64("local", acc.next), zap.Uint64("chain", pending))
+		acc.next = pending
+	}
+	i := sort.Search(len(acc.released), func(i int) bool { return acc.released[i] >= pending })
+	acc.released = acc.released[i:]
+	return acc
+}
@@ -0,0 +1,110 @@
+package util
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type fakeNonceSource struct {
+	mu      sync.Mutex
+	pending uint64
+}
+
+func (s *fakeNonceSource) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending, nil
+}
+
+func (s *fakeNonceSource) set(n uint64) {
+	s.mu.Lock()
+	s.pending = n
+	s.mu.Unlock()
+}
+
+func TestNonceManager(t *testing.T) {
+	ctx := context.Background()
+	addr := common.HexToAddress("0x01")
+	source := &fakeNonceSource{pending: 5}
+	nm := NewNonceManager(source)
+
+	reserve := func(n int) []uint64 {
+		t.Helper()
+		nonces, err := nm.ReserveN(ctx, addr, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return nonces
+	}
+
+	if got := reserve(3); !reflect.DeepEqual(got, []uint64{5, 6, 7}) {
+		t.Fatalf("ReserveN(3) = %v", got)
+	}
+	// a released gap is reused by single reservations only
+	nm.Release(addr, 6)
+	if got := reserve(2); !reflect.DeepEqual(got, []uint64{8, 9}) {
+		t.Fatalf("ReserveN(2) after release = %v", got)
+	}
+	if got := reserve(1); !reflect.DeepEqual(got, []uint64{6}) {
+		t.Fatalf("Reserve after release = %v", got)
+	}
+	// releasing the tail shrinks it
+	nm.Release(addr, 9, 8)
+	if got := reserve(1); !reflect.DeepEqual(got, []uint64{8}) {
+		t.Fatalf("Reserve after tail release = %v", got)
+	}
+	// nonces consumed externally move the account forward
+	source.set(20)
+	if got := reserve(1); !reflect.DeepEqual(got, []uint64{20}) {
+		t.Fatalf("Reserve after external use = %v", got)
+	}
+	source.set(3)
+	if err := nm.Resync(ctx, addr); err != nil {
+		t.Fatal(err)
+	}
+	if got := reserve(1); !reflect.DeepEqual(got, []uint64{3}) {
+		t.Fatalf("Reserve after resync = %v", got)
+	}
+}
+
+func TestNonceManagerReserveNInvalid(t *testing.T) {
+	nm := NewNonceManager(&fakeNonceSource{})
+	for _, n := range []int{0, -1} {
+		if nonces, err := nm.ReserveN(context.Background(), common.HexToAddress("0x01"), n); err == nil {
+			t.Fatalf("ReserveN(%d) = %v, want error", n, nonces)
+		}
+	}
+}
+
+func TestNonceManagerConcurrent(t *testing.T) {
+	nm := NewNonceManager(&fakeNonceSource{})
+	addr := common.HexToAddress("0x01")
+	var mu sync.Mutex
+	seen := make(map[uint64]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := nm.Reserve(context.Background(), addr)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if seen[n] {
+				t.Errorf("nonce %d reserved twice", n)
+			}
+			seen[n] = true
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 50 {
+		t.Fatalf("got %d distinct nonces, want 50", len(seen))
+	}
+}
@@ -0,0 +1,194 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	common2 "github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0} // Error(string)
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71} // Panic(uint256)
+)
+
+// panicReasons are the Solidity panic codes, see https://docs.soliditylang.org/en/latest/control-structures.html#panic-via-assert-and-error-via-require
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assert failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized function",
+}
+
+type RevertKind int
+
+const (
+	RevertUnknown RevertKind = iota
+	RevertEmpty
+	RevertError
+	RevertPanic
+	RevertCustom
+)
+
+// RevertReason is decoded revert data.
+type RevertReason struct {
+	Kind      RevertKind
+	Message   string        // Error(string) message, panic description or custom error signature
+	PanicCode *big.Int      // set for RevertPanic
+	ErrorName string        // set for RevertCustom
+	Args      []interface{} // set for RevertCustom
+	Data      []byte        // raw revert data
+}
+
+func (r *RevertReason) String() string {
+	switch r.Kind {
+	case RevertEmpty:
+		return "reverted without reason"
+	case RevertError:
+		return r.Message
+	case RevertPanic:
+		return fmt.Sprintf("panic 0x%x: %s", r.PanicCode, r.Message)
+	case RevertCustom:
+		return fmt.Sprintf("%s%v", r.ErrorName, r.Args)
+	default:
+		return fmt.Sprintf("unknown revert %s", hexutil.Encode(r.Data))
+	}
+}
+
+// DecodeRevert parses Error(string), Panic(uint256) and, when contractABI is not nil,
+// the custom errors it declares. Unrecognised data is returned as RevertUnknown.
+func DecodeRevert(data []byte, contractABI *abi.ABI) (*RevertReason, error) {
+	reason := &RevertReason{Kind: RevertUnknown, Data: data}
+	if len(data) == 0 {
+		reason.Kind = RevertEmpty
+		return reason, nil
+	}
+	if len(data) < 4 {
+		return reason, nil
+	}
+	selector := data[:4]
+	switch {
+	case bytes.Equal(selector, errorSelector):
+		msg, err := abi.UnpackRevert(data)
+		if err != nil {
+			return nil, err
+		}
+		reason.Kind = RevertError
+		reason.Message = msg
+		return reason, nil
+	case bytes.Equal(selector, panicSelector):
+		if len(data) != 4+32 {
+			return nil, errors.New("invalid panic data")
+		}
+		code := new(big.Int).SetBytes(data[4:])
+		reason.Kind = RevertPanic
+		reason.PanicCode = code
+		reason.Message = "unknown panic code"
+		if code.IsUint64() {
+			if msg, ok := panicReasons[code.Uint64()]; ok {
+				reason.Message = msg
+			}
+		}
+		return reason, nil
+	}
+	if contractABI == nil {
+		return reason, nil
+	}
+	for name, abiErr := range contractABI.Errors {
+		if !bytes.Equal(abiErr.ID[:4], selector) {
+			continue
+		}
+		args, err := abiErr.Inputs.Unpack(data[4:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack custom error %s: %w", name, err)
+		}
+		reason.Kind = RevertCustom
+		reason.ErrorName = name
+		reason.Message = abiErr.String()
+		reason.Args = args
+		return reason, nil
+	}
+	return reason, nil
+}
+
+// SimulationRevertData extracts the revert data of a simulated tx. The relay reports it
+// either hex encoded or as the raw bytes in a JSON string.
+func SimulationRevertData(res common2.TxSimulationResponse) []byte {
+	if res.Revert == nil {
+		return nil
+	}
+	if strings.HasPrefix(*res.Revert, "0x") {
+		if b, err := hexutil.Decode(*res.Revert); err == nil {
+			return b
+		}
+	}
+	return []byte(*res.Revert)
+}
+
+// TxRevert describes a failed tx of a simulated bundle.
+type TxRevert struct {
+	Index   int
+	TxHash  common.Hash
+	Error   string
+	Reason  *RevertReason
+	Allowed bool // listed in RevertingTxHashes
+}
+
+// RevertSummary lists the failing txs of a simulated bundle.
+type RevertSummary struct {
+	Reverts []TxRevert
+	// FirstFailure is the index in Reverts of the first tx not allowed to revert, or -1.
+	FirstFailure int
+}
+
+// OK reports whether every reverting tx is in the allowed list.
+func (s *RevertSummary) OK() bool {
+	return s.FirstFailure == -1
+}
+
+// SummarizeReverts decodes the reverts of res and checks them against allowed, the
+// RevertingTxHashes of the bundle. contractABI may be nil.
+func SummarizeReverts(res *common2.CallBundleResponse, allowed []string, contractABI *abi.ABI) *RevertSummary {
+	allowedSet := make(map[common.Hash]struct{}, len(allowed))
+	for _, h := range allowed {
+		allowedSet[common.HexToHash(h)] = struct{}{}
+	}
+	summary := &RevertSummary{FirstFailure: -1}
+	if res == nil {
+		return summary
+	}
+	for i, tx := range res.Results {
+		if tx.Error == nil && tx.Revert == nil {
+			continue
+		}
+		r := TxRevert{Index: i, TxHash: tx.TxHash}
+		if tx.Error != nil {
+			r.Error = *tx.Error
+		}
+		// Without revert data the tx failed for another reason, e.g. "out of gas", which
+		// Error already describes.
+		if tx.Revert != nil {
+			if reason, err := DecodeRevert(SimulationRevertData(tx), contractABI); err == nil {
+				r.Reason = reason
+			}
+		}
+		_, r.Allowed = allowedSet[tx.TxHash]
+		if !r.Allowed && summary.FirstFailure == -1 {
+			summary.FirstFailure = len(summary.Reverts)
+		}
+		summary.Reverts = append(summary.Reverts, r)
+	}
+	return summary
+}
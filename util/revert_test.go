@@ -0,0 +1,126 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	common2 "github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const testErrorsABI = `[{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}]`
+
+func TestDecodeRevert(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(testErrorsABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errorData := hexutil.MustDecode("0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000005" +
+		"6f6f707321000000000000000000000000000000000000000000000000000000")
+	panicData := append(append([]byte{}, panicSelector...), common.LeftPadBytes([]byte{0x11}, 32)...)
+	unknownPanic := append(append([]byte{}, panicSelector...), common.LeftPadBytes([]byte{0x99}, 32)...)
+	customData := append(crypto.Keccak256([]byte("InsufficientBalance(uint256,uint256)"))[:4],
+		append(common.LeftPadBytes([]byte{1}, 32), common.LeftPadBytes([]byte{2}, 32)...)...)
+
+	tests := []struct {
+		name     string
+		data     []byte
+		abi      *abi.ABI
+		wantKind RevertKind
+		wantStr  string
+		wantErr  bool
+	}{
+		{"empty", nil, nil, RevertEmpty, "reverted without reason", false},
+		{"short", []byte{0x01, 0x02}, nil, RevertUnknown, "unknown revert 0x0102", false},
+		{"error string", errorData, nil, RevertError, "oops!", false},
+		{"truncated error string", errorData[:40], nil, 0, "", true},
+		{"panic", panicData, nil, RevertPanic, "panic 0x11: arithmetic overflow or underflow", false},
+		{"unknown panic", unknownPanic, nil, RevertPanic, "panic 0x99: unknown panic code", false},
+		{"invalid panic", panicData[:20], nil, 0, "", true},
+		{"custom without abi", customData, nil, RevertUnknown, "unknown revert " + hexutil.Encode(customData), false},
+		{"custom", customData, &contractABI, RevertCustom, "InsufficientBalance[1 2]", false},
+		{"custom truncated", customData[:20], &contractABI, 0, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, err := DecodeRevert(tt.data, tt.abi)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeRevert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if reason.Kind != tt.wantKind || reason.String() != tt.wantStr {
+				t.Fatalf("DecodeRevert() = %d %q, want %d %q", reason.Kind, reason.String(), tt.wantKind, tt.wantStr)
+			}
+		})
+	}
+}
+
+func TestSimulationRevertData(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		revert *string
+		want   []byte
+	}{
+		{nil, nil},
+		{str("0x0102"), []byte{0x01, 0x02}},
+		{str("0xzz"), []byte("0xzz")},
+		{str("raw"), []byte("raw")},
+	}
+	for _, tt := range tests {
+		got := SimulationRevertData(common2.TxSimulationResponse{Revert: tt.revert})
+		if string(got) != string(tt.want) {
+			t.Fatalf("SimulationRevertData(%v) = %x, want %x", tt.revert, got, tt.want)
+		}
+	}
+}
+
+func TestSummarizeReverts(t *testing.T) {
+	str := func(s string) *string { return &s }
+	h1, h2, h3 := common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")
+	res := &common2.CallBundleResponse{Results: []common2.TxSimulationResponse{
+		{TxHash: h1},
+		{TxHash: h2, Error: str("execution reverted"), Revert: str("0x")},
+		{TxHash: h3, Error: str("out of gas")},
+	}}
+
+	tests := []struct {
+		name        string
+		allowed     []string
+		wantReverts int
+		wantFirst   int
+	}{
+		{"none allowed", nil, 2, 0},
+		{"first allowed", []string{h2.Hex()}, 2, 1},
+		{"all allowed", []string{h2.Hex(), h3.Hex()}, 2, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := SummarizeReverts(res, tt.allowed, nil)
+			if len(summary.Reverts) != tt.wantReverts || summary.FirstFailure != tt.wantFirst {
+				t.Fatalf("SummarizeReverts() = %d reverts, first failure %d, want %d, %d",
+					len(summary.Reverts), summary.FirstFailure, tt.wantReverts, tt.wantFirst)
+			}
+			if summary.OK() != (tt.wantFirst == -1) {
+				t.Fatalf("OK() = %v", summary.OK())
+			}
+		})
+	}
+
+	summary := SummarizeReverts(res, nil, nil)
+	if r := summary.Reverts[0]; r.Reason == nil || r.Reason.Kind != RevertEmpty {
+		t.Fatalf("revert with empty data = %+v, want RevertEmpty", r.Reason)
+	}
+	if r := summary.Reverts[1]; r.Reason != nil || r.Error != "out of gas" {
+		t.Fatalf("failure without revert data = %+v, want error only", r)
+	}
+	if s := SummarizeReverts(nil, nil, nil); !s.OK() || len(s.Reverts) != 0 {
+		t.Fatalf("SummarizeReverts(nil) = %+v", s)
+	}
+}
@@ -0,0 +1,330 @@
+package util
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	common2 "github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+// Backend is the subset of ethclient.Client used by TxFactory.
+type Backend interface {
+	NonceSource
+	GasPriceSuggester
+	ChainID(ctx context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+}
+
+// AccessListCreator generates access lists with eth_createAccessList, implemented by
+// gethclient.Client.
+type AccessListCreator interface {
+	CreateAccessList(ctx context.Context, msg ethereum.CallMsg) (*types.AccessList, uint64, string, error)
+}
+
+// TxFactoryConfig configures a TxFactory. Only Keys is required.
+type TxFactoryConfig struct {
+	Keys              []*ecdsa.PrivateKey // signing keys, the first one is the default sender
+	NonceManager      NonceManager        // defaults to a NonceManager over the backend
+	FeeStrategy       FeeStrategy         // defaults to SuggestedFee
+	TargetBlockOffset uint64              // blocks ahead of the current head to target, defaults to 1
+	GasLimitMargin    uint64              // percentage added on top of estimated gas, e.g. 20
+	AccessListCreator AccessListCreator   // needed for TxOpts.CreateAccessList
+}
+
+// TxOpts describes a single transaction. Zero values fall back to the factory defaults.
+type TxOpts struct {
+	From     common.Address  // sender, must be one of the factory keys
+	To       *common.Address // nil for contract creation
+	Value    *big.Int
+	Data     []byte
+	GasLimit uint64      // estimated when zero
+	Nonce    *uint64     // reserved through the NonceManager when nil
+	Fee      FeeStrategy // overrides the factory fee strategy
+	// Type is types.DynamicFeeTxType when zero, or types.AccessListTxType, which pays the
+	// base fee projected for the target block plus GasTipCap as gas price and needs a
+	// Backend implementing HeaderReader. Blob txs are rejected with
+	// common.ErrBlobTxUnsupported until this module moves to a go-ethereum release with
+	// EIP-4844 support.
+	Type       uint8
+	AccessList types.AccessList
+	// CreateAccessList replaces AccessList with the one returned by eth_createAccessList,
+	// whose gas estimate is then used when GasLimit is zero.
+	CreateAccessList bool
+}
+
+// TxFactory builds and signs EIP-1559 and EIP-2930 transactions for bundles.
+type TxFactory struct {
+	logger  *zap.Logger
+	backend Backend
+	keys    map[common.Address]*ecdsa.PrivateKey
+	from    common.Address
+	cfg     TxFactoryConfig
+
+	mu      sync.Mutex // protects chainID
+	chainID *big.Int
+}
+
+func NewTxFactory(backend Backend, cfg TxFactoryConfig) (*TxFactory, error) {
+	if backend == nil {
+		return nil, errors.New("tx factory: nil backend")
+	}
+	if len(cfg.Keys) == 0 {
+		return nil, errors.New("tx factory: no signing keys")
+	}
+	keys := make(map[common.Address]*ecdsa.PrivateKey, len(cfg.Keys))
+	for _, key := range cfg.Keys {
+		keys[crypto.PubkeyToAddress(key.PublicKey)] = key
+	}
+	if cfg.NonceManager == nil {
+		cfg.NonceManager = NewNonceManager(backend)
+	}
+	if cfg.FeeStrategy == nil {
+		cfg.FeeStrategy = SuggestedFee(backend)
+	}
+	if cfg.TargetBlockOffset == 0 {
+		cfg.TargetBlockOffset = 1
+	}
+	return &TxFactory{
+		logger:  common2.NewLogger(),
+		backend: backend,
+		keys:    keys,
+		from:    crypto.PubkeyToAddress(cfg.Keys[0].PublicKey),
+		cfg:     cfg,
+	}, nil
+}
+
+// ChainID returns the backend chain id, cached after the first call.
+func (f *TxFactory) ChainID(ctx context.Context) (*big.Int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.chainID != nil {
+		return f.chainID, nil
+	}
+	id, err := f.backend.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	f.chainID = id
+	return id, nil
+}
+
+// TargetBlock returns the current head plus the configured offset.
+func (f *TxFactory) TargetBlock(ctx context.Context) (uint64, error) {
+	head, err := f.backend.BlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return head + f.cfg.TargetBlockOffset, nil
+}
+
+// CreateTx builds and signs a transaction. A reserved nonce is released again when
+// signing fails; callers should release it via NonceManager when submission fails.
+func (f *TxFactory) CreateTx(ctx context.Context, opts TxOpts) (*types.Transaction, error) {
+	from := opts.From
+	if from == (common.Address{}) {
+		from = f.from
+	}
+	key, ok := f.keys[from]
+	if !ok {
+		return nil, fmt.Errorf("tx factory: no key for sender %s", from.Hex())
+	}
+	switch opts.Type {
+	case 0, types.DynamicFeeTxType, types.AccessListTxType:
+	case common2.BlobTxType:
+		return nil, fmt.Errorf("tx factory: %w", common2.ErrBlobTxUnsupported)
+	default:
+		return nil, fmt.Errorf("tx factory: unsupported tx type %d", opts.Type)
+	}
+	chainID, err := f.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain id: %w", err)
+	}
+
+	gasLimit := opts.GasLimit
+	if opts.CreateAccessList {
+		var gasUsed uint64
+		opts.AccessList, gasUsed, err = f.createAccessList(ctx, from, opts)
+		if err != nil {
+			return nil, err
+		}
+		if gasLimit == 0 {
+			gasLimit = gasUsed + gasUsed*f.cfg.GasLimitMargin/100
+		}
+	}
+	if gasLimit == 0 {
+		gasLimit, err = f.estimateGas(ctx, from, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	feeStrategy := opts.Fee
+	if feeStrategy == nil {
+		feeStrategy = f.cfg.FeeStrategy
+	}
+	fees, err := feeStrategy.Fees(ctx, gasLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to price tx: %w", err)
+	}
+
+	var nonce uint64
+	reserved := opts.Nonce == nil
+	if reserved {
+		nonce, err = f.cfg.NonceManager.Reserve(ctx, from)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve nonce: %w", err)
+		}
+	} else {
+		nonce = *opts.Nonce
+	}
+
+	value := opts.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	var tx *types.Transaction
+	if opts.Type == types.AccessListTxType {
+		gasPrice, err := f.accessListGasPrice(ctx, fees)
+		if err != nil {
+			if reserved {
+				f.cfg.NonceManager.Release(from, nonce)
+			}
+			return nil, err
+		}
+		tx = types.NewTx(&types.AccessListTx{
+			ChainID:    chainID,
+			Nonce:      nonce,
+			GasPrice:   gasPrice,
+			Gas:        gasLimit,
+			To:         opts.To,
+			Value:      value,
+			Data:       opts.Data,
+			AccessList: opts.AccessList,
+		})
+	} else {
+		tx = types.NewTx(&types.DynamicFeeTx{
+			ChainID:    chainID,
+			Nonce:      nonce,
+			GasTipCap:  fees.GasTipCap,
+			GasFeeCap:  fees.GasFeeCap,
+			Gas:        gasLimit,
+			To:         opts.To,
+			Value:      value,
+			Data:       opts.Data,
+			AccessList: opts.AccessList,
+		})
+	}
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), key)
+	if err != nil {
+		if reserved {
+			f.cfg.NonceManager.Release(from, nonce)
+		}
+		return nil, fmt.Errorf("failed to sign tx: %w", err)
+	}
+	f.logger.Debug("created tx", zap.String("hash", signedTx.Hash().Hex()), zap.Uint64("nonce", nonce), zap.Uint64("gasLimit", gasLimit))
+	return signedTx, nil
+}
+
+// ReleaseTxs hands the nonces of txs back to the NonceManager, e.g. after a bundle was rejected.
+func (f *TxFactory) ReleaseTxs(txs ...*types.Transaction) {
+	f.mu.Lock()
+	chainID := f.chainID
+	f.mu.Unlock()
+	if chainID == nil {
+		return
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	for _, tx := range txs {
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			continue
+		}
+		f.cfg.NonceManager.Release(from, tx.Nonce())
+	}
+}
+
+// SendBundleArgs encodes txs into bundle arguments targeting TargetBlock.
+func (f *TxFactory) SendBundleArgs(ctx context.Context, txs ...*types.Transaction) (*common2.SendBundleArgs, error) {
+	target, err := f.TargetBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rawTxs := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		b, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		rawTxs = append(rawTxs, hexutil.Encode(b))
+	}
+	return &common2.SendBundleArgs{
+		Txs:         rawTxs,
+		BlockNumber: hexutil.EncodeUint64(target),
+	}, nil
+}
+
+func (f *TxFactory) estimateGas(ctx context.Context, from common.Address, opts TxOpts) (uint64, error) {
+	gas, err := f.backend.EstimateGas(ctx, ethereum.CallMsg{
+		From:       from,
+		To:         opts.To,
+		Value:      opts.Value,
+		Data:       opts.Data,
+		AccessList: opts.AccessList,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	return gas + gas*f.cfg.GasLimitMargin/100, nil
+}
+
+// accessListGasPrice prices an EIP-2930 tx, which pays its gas price in full, at the base
+// fee projected for the target block plus the tip, rather than at the fee cap.
+func (f *TxFactory) accessListGasPrice(ctx context.Context, fees *Fees) (*big.Int, error) {
+	headers, ok := f.backend.(HeaderReader)
+	if !ok {
+		return nil, errors.New("tx factory: access list txs need a backend implementing HeaderReader")
+	}
+	head, err := headers.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get head: %w", err)
+	}
+	gasPrice := MaxBaseFee(NextBaseFee(head), f.cfg.TargetBlockOffset-1)
+	if fees.GasTipCap != nil {
+		gasPrice.Add(gasPrice, fees.GasTipCap)
+	}
+	return gasPrice, nil
+}
+
+func (f *TxFactory) createAccessList(ctx context.Context, from common.Address, opts TxOpts) (types.AccessList, uint64, error) {
+	if f.cfg.AccessListCreator == nil {
+		return nil, 0, errors.New("tx factory: no AccessListCreator configured")
+	}
+	accessList, gasUsed, vmErr, err := f.cfg.AccessListCreator.CreateAccessList(ctx, ethereum.CallMsg{
+		From:       from,
+		To:         opts.To,
+		Value:      opts.Value,
+		Data:       opts.Data,
+		AccessList: opts.AccessList,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create access list: %w", err)
+	}
+	if vmErr != "" {
+		return nil, 0, fmt.Errorf("failed to create access list: execution reverted: %s", vmErr)
+	}
+	if accessList == nil {
+		return nil, gasUsed, nil
+	}
+	return *accessList, gasUsed, nil
+}
@@ -0,0 +1,211 @@
+package util
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+
+	common2 "github.com/bhakiyakalimuthu/flashbots-rpc-client/common"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+type fakeBackend struct {
+	pending uint64
+}
+
+func (b *fakeBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return b.pending, nil
+}
+
+func (b *fakeBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(2), nil
+}
+
+func (b *fakeBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(100), nil
+}
+
+func (b *fakeBackend) ChainID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1), nil
+}
+
+func (b *fakeBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	return 10, nil
+}
+
+func (b *fakeBackend) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return 21000, nil
+}
+
+// fakeHeaderBackend also serves a full parent block with a base fee of 1000.
+type fakeHeaderBackend struct {
+	fakeBackend
+}
+
+func (b *fakeHeaderBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: big.NewInt(1000), GasLimit: 30_000_000, GasUsed: 30_000_000}, nil
+}
+
+func TestTxFactoryTxTypes(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x01")
+	tests := []struct {
+		name         string
+		backend      Backend
+		offset       uint64
+		txType       uint8
+		wantType     uint8
+		wantGasPrice int64
+		wantErr      error
+	}{
+		{"default", &fakeBackend{}, 1, 0, types.DynamicFeeTxType, 200, nil},
+		{"dynamic fee", &fakeBackend{}, 1, types.DynamicFeeTxType, types.DynamicFeeTxType, 200, nil},
+		{"access list", &fakeHeaderBackend{}, 1, types.AccessListTxType, types.AccessListTxType, 1125 + 2, nil},
+		{"access list two blocks ahead", &fakeHeaderBackend{}, 2, types.AccessListTxType, types.AccessListTxType, 1265 + 2, nil},
+		{"access list without headers", &fakeBackend{}, 1, types.AccessListTxType, 0, 0, errors.New("")},
+		{"blob", &fakeBackend{}, 1, common2.BlobTxType, 0, 0, common2.ErrBlobTxUnsupported},
+		{"unknown", &fakeBackend{}, 1, 0x7f, 0, 0, errors.New("")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewTxFactory(tt.backend, TxFactoryConfig{Keys: []*ecdsa.PrivateKey{key}, TargetBlockOffset: tt.offset})
+			if err != nil {
+				t.Fatal(err)
+			}
+			tx, err := f.CreateTx(context.Background(), TxOpts{To: &to, Type: tt.txType})
+			if tt.wantErr != nil {
+				if err == nil || (tt.wantErr.Error() != "" && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("CreateTx() error = %v, want %v", err, tt.wantErr)
+				}
+				// the nonce of a failed tx is handed out again
+				if tx, err = f.CreateTx(context.Background(), TxOpts{To: &to}); err != nil || tx.Nonce() != 0 {
+					t.Fatalf("next tx nonce = %v, %v, want 0", tx, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tx.Type() != tt.wantType {
+				t.Fatalf("tx type = %d, want %d", tx.Type(), tt.wantType)
+			}
+			if tx.GasFeeCap().Int64() != tt.wantGasPrice {
+				t.Fatalf("gas price = %v, want %d", tx.GasFeeCap(), tt.wantGasPrice)
+			}
+		})
+	}
+}
+
+type failingFee struct{}
+
+func (failingFee) Fees(ctx context.Context, gasLimit uint64) (*Fees, error) {
+	return nil, errors.New("no fee")
+}
+
+func TestTxFactoryCreateTx(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x01")
+	nonce := uint64(7)
+	tests := []struct {
+		name      string
+		margin    uint64
+		opts      TxOpts
+		wantGas   uint64
+		wantNonce uint64
+		wantNext  uint64 // nonce of the following tx
+		wantErr   bool
+	}{
+		{"estimated gas", 0, TxOpts{To: &to}, 21000, 5, 6, false},
+		{"estimated gas with margin", 20, TxOpts{To: &to}, 25200, 5, 6, false},
+		{"explicit gas limit", 20, TxOpts{To: &to, GasLimit: 50000}, 50000, 5, 6, false},
+		{"explicit nonce", 0, TxOpts{To: &to, Nonce: &nonce}, 21000, 7, 5, false},
+		{"explicit sender", 0, TxOpts{From: crypto.PubkeyToAddress(key.PublicKey), To: &to}, 21000, 5, 6, false},
+		{"unknown sender", 0, TxOpts{From: crypto.PubkeyToAddress(other.PublicKey), To: &to}, 0, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewTxFactory(&fakeBackend{pending: 5}, TxFactoryConfig{Keys: []*ecdsa.PrivateKey{key}, GasLimitMargin: tt.margin})
+			if err != nil {
+				t.Fatal(err)
+			}
+			tx, err := f.CreateTx(context.Background(), tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateTx() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tx.Gas() != tt.wantGas {
+				t.Fatalf("gas = %d, want %d", tx.Gas(), tt.wantGas)
+			}
+			if tx.Nonce() != tt.wantNonce {
+				t.Fatalf("nonce = %d, want %d", tx.Nonce(), tt.wantNonce)
+			}
+			from, err := types.Sender(types.LatestSignerForChainID(big.NewInt(1)), tx)
+			if err != nil || from != crypto.PubkeyToAddress(key.PublicKey) {
+				t.Fatalf("sender = %s, %v", from.Hex(), err)
+			}
+			// an explicit nonce must not consume one from the manager
+			next, err := f.CreateTx(context.Background(), TxOpts{To: &to})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next.Nonce() != tt.wantNext {
+				t.Fatalf("next nonce = %d, want %d", next.Nonce(), tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestTxFactoryCreateTxKeepsNonce(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a zero scalar keeps the public key but cannot sign
+	unusable := &ecdsa.PrivateKey{PublicKey: key.PublicKey, D: new(big.Int)}
+	to := common.HexToAddress("0x01")
+	tests := []struct {
+		name string
+		key  *ecdsa.PrivateKey
+		fee  FeeStrategy
+	}{
+		{"pricing failure", key, failingFee{}},
+		{"signing failure", unusable, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &fakeBackend{pending: 5}
+			nm := NewNonceManager(backend)
+			f, err := NewTxFactory(backend, TxFactoryConfig{Keys: []*ecdsa.PrivateKey{tt.key}, NonceManager: nm, FeeStrategy: tt.fee})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := f.CreateTx(context.Background(), TxOpts{To: &to}); err == nil {
+				t.Fatal("CreateTx() succeeded, want error")
+			}
+			nonce, err := nm.Reserve(context.Background(), crypto.PubkeyToAddress(key.PublicKey))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if nonce != 5 {
+				t.Fatalf("next nonce = %d, want 5", nonce)
+			}
+		})
+	}
+}